@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newAttachCommand(app **jnal.App) *cobra.Command {
+	var (
+		date string
+		name string
+		move bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "attach <file>",
+		Short: "Attach a file to a journal entry",
+		Long: `Copy (or move) a file into the attachments directory of a journal entry
+and append a Markdown link (or image reference) to the entry.
+Files whose content is already attached are reused; existing attachments are never overwritten.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetDate, err := util.Parse(date)
+			if err != nil {
+				return fmt.Errorf("invalid date %q: %w", date, err)
+			}
+
+			attachment, err := (*app).Journal().Attach(targetDate, args[0], jnal.AttachOptions{
+				Name: name,
+				Move: move,
+			})
+			if err != nil {
+				return fmt.Errorf("attaching file: %w", err)
+			}
+
+			warnCommit((*app).Journal().CommitEntry(targetDate, attachment.Path))
+
+			if attachment.Reused {
+				fmt.Printf("Already attached as %s\n", attachment.Path)
+			}
+			fmt.Println(attachment.Link)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&date, "date", "d",
+		util.Format(util.Today()),
+		"Date for the journal entry (format: yyyy-mm-dd)")
+	cmd.Flags().StringVarP(&name, "name", "n", "", "Attachment file name (default: source file name)")
+	cmd.Flags().BoolVarP(&move, "move", "m", false, "Move the file instead of copying it")
+
+	return cmd
+}
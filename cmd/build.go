@@ -2,28 +2,101 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/longkey1/jnal/internal/jnal"
 	"github.com/longkey1/jnal/internal/server"
+	"github.com/longkey1/jnal/internal/util"
 	"github.com/spf13/cobra"
 )
 
+// cssFlagUsage describes the --css flag of build and serve
+const cssFlagUsage = "Stylesheet: built-in theme name, URL, .css file or inline CSS (overrides build.css)"
+
+// cssFlag returns the --css value, with a file path made absolute, since paths in
+// the config are relative to the config file instead of the working directory
+func cssFlag(value string) string {
+	if info, err := os.Stat(value); err == nil && !info.IsDir() {
+		if abs, err := filepath.Abs(value); err == nil {
+			return abs
+		}
+	}
+	return value
+}
+
 func newBuildCommand(app **jnal.App) *cobra.Command {
-	var output string
+	var (
+		output    string
+		noCache   bool
+		jobs      int
+		printBook bool
+		from      string
+		to        string
+		drafts    bool
+		future    bool
+		css       string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "build",
 		Short: "Build static HTML files",
-		Long:  `Generate static HTML files from journal entries.`,
+		Long: `Generate static HTML files from journal entries.
+
+With --print, generate a print-ready book instead: a cover page, a table of
+contents by year and month, and each month starting on a new page. Open it in
+a browser and print it to paper or PDF.
+
+Entries marked draft: true and entries with a publish_date after today are
+left out unless --drafts or --future is given.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := (*app).Config()
 			jnl := (*app).Journal()
 
+			// Override config with command line flags
+			if noCache {
+				cache := false
+				cfg.Build.Cache = &cache
+			}
+			if cmd.Flags().Changed("jobs") {
+				cfg.Build.Jobs = jobs
+			}
+			if cmd.Flags().Changed("drafts") {
+				cfg.Build.Drafts = drafts
+			}
+			if cmd.Flags().Changed("future") {
+				cfg.Build.Future = future
+			}
+			if cmd.Flags().Changed("css") {
+				cfg.Build.CSS = cssFlag(css)
+			}
+
+			if !printBook && (from != "" || to != "") {
+				return fmt.Errorf("--from and --to can only be used with --print")
+			}
+			dateRange, err := util.ParseRange(from, to)
+			if err != nil {
+				return err
+			}
+
+			// Validate config
+			if err := cfg.Validate(); err != nil {
+				return fmt.Errorf("invalid config: %w", err)
+			}
+
 			builder, err := server.NewBuilder(cfg, jnl, cfg.Common.BaseDirectory)
 			if err != nil {
 				return fmt.Errorf("creating builder: %w", err)
 			}
 
+			if printBook {
+				if err := builder.BuildPrint(output, dateRange); err != nil {
+					return fmt.Errorf("building: %w", err)
+				}
+				fmt.Printf("Built print book to %s\n", output)
+				return nil
+			}
+
 			if err := builder.Build(output); err != nil {
 				return fmt.Errorf("building: %w", err)
 			}
@@ -34,6 +107,14 @@ func newBuildCommand(app **jnal.App) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&output, "output", "o", "public", "Output directory")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Disable the render cache")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 0, "Number of entries rendered concurrently (default: GOMAXPROCS)")
+	cmd.Flags().BoolVar(&printBook, "print", false, "Build a print-ready book")
+	cmd.Flags().StringVar(&css, "css", "", cssFlagUsage)
+	cmd.Flags().BoolVar(&drafts, "drafts", false, "Include draft entries")
+	cmd.Flags().BoolVar(&future, "future", false, "Include entries with a publish_date after today")
+	cmd.Flags().StringVar(&from, "from", "", "Include entries from this date in the print book (format: yyyy-mm-dd)")
+	cmd.Flags().StringVar(&to, "to", "", "Include entries up to this date in the print book (format: yyyy-mm-dd)")
 
 	return cmd
 }
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/term"
+	"github.com/longkey1/jnal/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// calYearColumns is the number of months per row in the year view
+const calYearColumns = 3
+
+func newCalCommand(app **jnal.App) *cobra.Command {
+	var (
+		year        int
+		month       string
+		weekStart   string
+		weekNumbers bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cal",
+		Short: "Show a calendar of journal entries",
+		Long: `Show a cal(1)-style calendar with the days that have journal entries highlighted.
+Days are colored when the output is a terminal, and marked with * otherwise.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := (*app).Config()
+
+			// Override config with command line flags
+			if cmd.Flags().Changed("week-start") {
+				cfg.Cal.WeekStart = weekStart
+			}
+			if cmd.Flags().Changed("week-numbers") {
+				cfg.Cal.WeekNumbers = weekNumbers
+			}
+			if err := cfg.Cal.Validate(); err != nil {
+				return fmt.Errorf("invalid config: %w", err)
+			}
+
+			if year != 0 && month != "" {
+				return fmt.Errorf("--year and --month cannot be used together")
+			}
+
+			today := util.Today()
+			var months []time.Time
+			switch {
+			case year != 0:
+				for m := time.January; m <= time.December; m++ {
+					months = append(months, time.Date(year, m, 1, 0, 0, 0, 0, time.UTC))
+				}
+			case month != "":
+				first, err := time.Parse("2006-01", month)
+				if err != nil {
+					return fmt.Errorf("invalid month %q: expected yyyy-mm", month)
+				}
+				months = append(months, first)
+			default:
+				months = append(months, time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC))
+			}
+
+			entries, err := (*app).Journal().ListEntries()
+			if err != nil {
+				return fmt.Errorf("listing entries: %w", err)
+			}
+			days := make(map[string]bool, len(entries))
+			for _, e := range entries {
+				days[util.Format(e.Date)] = true
+			}
+
+			fmt.Print(term.Months(months, calYearColumns, term.CalendarOptions{
+				WeekStart:   cfg.Cal.GetWeekStart(),
+				WeekNumbers: cfg.Cal.WeekNumbers,
+				Color:       term.IsTerminal(os.Stdout) && os.Getenv("NO_COLOR") == "",
+				Marked: func(d time.Time) bool {
+					return days[util.Format(d)]
+				},
+				Today: today,
+			}))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&year, "year", "y", 0, "Show all months of the year")
+	cmd.Flags().StringVarP(&month, "month", "m", "", "Show the month (format: yyyy-mm, default: current month)")
+	cmd.Flags().StringVar(&weekStart, "week-start", config.WeekStartSunday, "First day of the week: sunday, monday")
+	cmd.Flags().BoolVarP(&weekNumbers, "week-numbers", "w", false, "Show ISO week numbers")
+
+	return cmd
+}
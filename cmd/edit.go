@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newEditCommand(app **jnal.App) *cobra.Command {
+	var date string
+
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit a journal entry",
+		Long: `Open the journal entry for the specified date (or today if not specified)
+in $VISUAL or $EDITOR, creating it first if it doesn't exist.
+
+Encrypted entries are decrypted to a private temporary file, which is
+re-encrypted and securely removed when the editor exits.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetDate, err := util.Parse(date)
+			if err != nil {
+				return fmt.Errorf("invalid date %q: %w", date, err)
+			}
+
+			changed, err := (*app).Journal().EditEntry(targetDate, runEditor)
+			if err != nil {
+				return fmt.Errorf("editing entry: %w", err)
+			}
+
+			if changed {
+				warnCommit((*app).Journal().CommitEntry(targetDate))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&date, "date", "d",
+		util.Format(util.Today()),
+		"Date for the journal entry (format: yyyy-mm-dd)")
+
+	return cmd
+}
+
+// runEditor opens the file in $VISUAL or $EDITOR (default: vi) and waits for it to exit
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// Run through the shell so that editors with arguments ("code --wait") work
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running editor %q: %w", editor, err)
+	}
+	return nil
+}
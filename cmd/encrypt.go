@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/spf13/cobra"
+)
+
+func newEncryptCommand(app **jnal.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt all plain entries",
+		Long: `Encrypt all plain .md entries to .md.age files with the identity file of the
+[encryption] config, and overwrite and remove the plain files.
+
+Attachments are not encrypted. Plain versions of entries remain in the history
+of a git repository and in backups.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, err := (*app).Journal().EncryptEntries()
+			for _, p := range paths {
+				fmt.Printf("Encrypted %s\n", p)
+			}
+			if err != nil {
+				return fmt.Errorf("encrypting entries: %w", err)
+			}
+
+			if len(paths) > 0 {
+				warnCommit((*app).Journal().CommitChanges(fmt.Sprintf("encrypt: %d entries", len(paths))))
+			}
+			fmt.Printf("%d entries encrypted\n", len(paths))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newDecryptCommand(app **jnal.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decrypt",
+		Short: "Decrypt all encrypted entries",
+		Long: `Decrypt all .md.age entries back to plain .md files, for example before
+disabling encryption.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, err := (*app).Journal().DecryptEntries()
+			for _, p := range paths {
+				fmt.Printf("Decrypted %s\n", p)
+			}
+			if err != nil {
+				return fmt.Errorf("decrypting entries: %w", err)
+			}
+
+			if len(paths) > 0 {
+				warnCommit((*app).Journal().CommitChanges(fmt.Sprintf("decrypt: %d entries", len(paths))))
+			}
+			fmt.Printf("%d entries decrypted\n", len(paths))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
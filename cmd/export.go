@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/server"
+	"github.com/longkey1/jnal/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// exportFormatEPUB exports an EPUB book rendered like the HTML output
+const exportFormatEPUB = "epub"
+
+func newExportCommand(app **jnal.App) *cobra.Command {
+	var (
+		format string
+		from   string
+		to     string
+		year   int
+		output string
+	)
+
+	formats := append(slices.Clone(jnal.ExportFormats), exportFormatEPUB)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export journal entries to a single file",
+		Long: `Export journal entries in chronological order to a single Markdown, JSON,
+JSON Lines, CSV or EPUB file. JSON and CSV exports include the path, date, raw
+content and front matter of each entry. EPUB books have one chapter per month.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := (*app).Config()
+
+			if !slices.Contains(formats, format) {
+				return fmt.Errorf("invalid format: %s (must be one of: %s)", format, strings.Join(formats, ", "))
+			}
+			if year != 0 && (from != "" || to != "") {
+				return fmt.Errorf("--year cannot be used together with --from or --to")
+			}
+			if year != 0 {
+				from = strconv.Itoa(year) + "-01-01"
+				to = strconv.Itoa(year) + "-12-31"
+			}
+
+			dateRange, err := util.ParseRange(from, to)
+			if err != nil {
+				return err
+			}
+
+			toStdout := output == "" || output == "-"
+			if format == exportFormatEPUB && toStdout {
+				return fmt.Errorf("--output is required for epub")
+			}
+
+			var w io.Writer = os.Stdout
+			var f *os.File
+			if !toStdout {
+				f, err = os.Create(output)
+				if err != nil {
+					return fmt.Errorf("creating %s: %w", output, err)
+				}
+				defer f.Close()
+				w = f
+			}
+
+			if format == exportFormatEPUB {
+				if err := cfg.Validate(); err != nil {
+					return fmt.Errorf("invalid config: %w", err)
+				}
+				var builder *server.Builder
+				builder, err = server.NewBuilder(cfg, (*app).Journal(), cfg.Common.BaseDirectory)
+				if err == nil {
+					err = builder.BuildEPUB(w, dateRange)
+				}
+			} else {
+				err = (*app).Journal().Export(w, dateRange, jnal.ExportOptions{
+					Format:       format,
+					Title:        cfg.Build.Title,
+					HeadingShift: cfg.Build.GetHeadingShift(),
+				})
+			}
+			if err != nil {
+				if f != nil {
+					f.Close()
+					os.Remove(output)
+				}
+				return fmt.Errorf("exporting: %w", err)
+			}
+
+			if f != nil {
+				if err := f.Close(); err != nil {
+					return fmt.Errorf("closing %s: %w", output, err)
+				}
+				fmt.Fprintf(os.Stderr, "Exported to %s\n", output)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", jnal.ExportMarkdown, "Output format: "+strings.Join(formats, ", "))
+	cmd.Flags().StringVar(&from, "from", "", "Export entries from this date (format: yyyy-mm-dd)")
+	cmd.Flags().StringVar(&to, "to", "", "Export entries up to this date (format: yyyy-mm-dd)")
+	cmd.Flags().IntVarP(&year, "year", "y", 0, "Export entries of this year")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default: stdout, required for epub)")
+
+	return cmd
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/spf13/cobra"
+)
+
+func newImportCommand(app **jnal.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import entries from other journaling tools",
+	}
+
+	cmd.AddCommand(newImportDayOneCommand(app))
+	cmd.AddCommand(newImportFilesCommand(app))
+
+	return cmd
+}
+
+func newImportDayOneCommand(app **jnal.App) *cobra.Command {
+	var merge string
+
+	cmd := &cobra.Command{
+		Use:   "dayone <export.zip|journal.json>",
+		Short: "Import a Day One JSON export",
+		Long: `Import a Day One JSON export, either the exported zip file or an extracted
+journal JSON file with its photos directory next to it.
+
+Tags, location, weather and the starred flag are written to front matter and
+photos are copied into the attachments directory. Existing entry files are
+never overwritten; days that already have an entry are skipped.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := (*app).Journal().ImportDayOne(args[0], jnal.DayOneOptions{Merge: merge})
+			if result != nil {
+				printImportResult(result, false)
+				commitImport((*app).Journal(), result, args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("importing: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&merge, "merge", jnal.MergeAppend,
+		"Strategy for entries on the same day: "+strings.Join(jnal.MergeStrategies, ", "))
+
+	return cmd
+}
+
+// printImportResult prints a summary of an import
+func printImportResult(result *jnal.ImportResult, dryRun bool) {
+	prefix := ""
+	if dryRun {
+		prefix = "[dry run] "
+	}
+
+	printFiles := func(action string, files []jnal.ImportedFile, note string) {
+		for _, f := range files {
+			line := prefix + action + " " + f.Path
+			if f.Source != "" {
+				line += " from " + f.Source
+			}
+			fmt.Println(line + note)
+		}
+	}
+	printFiles("Created", result.Created, "")
+	printFiles("Appended to", result.Appended, "")
+	printFiles("Skipped", result.Skipped, " (already exists)")
+
+	for _, w := range result.Warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	fmt.Printf("%d created, %d appended, %d skipped, %d attachments\n",
+		len(result.Created), len(result.Appended), len(result.Skipped), result.Attachments)
+}
+
+// commitImport auto-commits the entries written by an import
+func commitImport(journal *jnal.Journal, result *jnal.ImportResult, source string) {
+	if n := len(result.Created) + len(result.Appended); n > 0 {
+		warnCommit(journal.CommitChanges(fmt.Sprintf("import: %d entries from %s", n, filepath.Base(source))))
+	}
+}
+
+func newImportFilesCommand(app **jnal.App) *cobra.Command {
+	var (
+		dateSources []string
+		conflict    string
+		dryRun      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "files <dir>",
+		Short: "Import a directory of Markdown and text files",
+		Long: `Import the Markdown (.md, .markdown) and text (.txt) files in a directory,
+such as Obsidian daily notes, as journal entries written according to path_format.
+
+The date of each file is taken from the first of these sources that has one:
+the file name, the "date" or "created" front matter field, the first heading,
+or the modification time. Files are copied; the originals are left untouched.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := (*app).Config()
+
+			// Override config with command line flags
+			if cmd.Flags().Changed("date-sources") {
+				cfg.Import.DateSources = dateSources
+			}
+			if cmd.Flags().Changed("conflict") {
+				cfg.Import.Conflict = conflict
+			}
+
+			if err := cfg.Import.Validate(); err != nil {
+				return fmt.Errorf("invalid config: %w", err)
+			}
+
+			result, err := (*app).Journal().ImportFiles(args[0], jnal.FilesOptions{
+				DateSources: cfg.Import.DateSources,
+				Conflict:    cfg.Import.Conflict,
+				DryRun:      dryRun,
+			})
+			if result != nil {
+				printImportResult(result, dryRun)
+				if !dryRun {
+					commitImport((*app).Journal(), result, args[0])
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("importing: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&dateSources, "date-sources", nil,
+		"Date sources in order of priority: filename, front_matter, heading, mtime (default from config)")
+	cmd.Flags().StringVar(&conflict, "conflict", "",
+		"What to do when an entry for the date exists: skip, append, rename (default from config)")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Show what would be imported without writing anything")
+
+	return cmd
+}
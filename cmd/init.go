@@ -23,10 +23,43 @@ file_template = "# {{ .Date }}\n"
 title = "Journal"
 sort = "desc"
 # heading_shift = 4  # Shift heading levels in HTML output (0 to disable)
-# css = "https://cdn.jsdelivr.net/npm/water.css@2/out/water.css"
+# toc = false           # Add a table of contents to entries with many headings
+# toc_min_headings = 3  # Headings an entry needs for a table of contents
+# css = "https://cdn.jsdelivr.net/npm/water.css@2/out/water.css"  # Or a theme (minimal, paper, terminal) or a .css file
+# css_dark = ""             # Stylesheet replacing css in dark mode
+# dark_mode_toggle = true   # Show a light/dark switch when the stylesheets support both
+# cache = true  # Cache rendered entries in $XDG_CACHE_HOME/jnal
+# jobs = 0      # Number of entries rendered concurrently (0 = number of CPUs)
+# drafts = false  # Include entries marked draft: true
+# future = false  # Include entries with a publish_date after today
+# template_dir = "templates"  # Templates overriding the built-in ones (jnal theme eject)
+# theme = "portal"            # Use the templates in themes/portal next to this file
 
 [serve]
 port = 8080
+
+[cal]
+week_start = "sunday"  # sunday or monday
+# week_numbers = true  # Show ISO week numbers
+
+[attach]
+# directory = "attachments/{{ .Name }}"  # Relative to the entry's directory
+
+[import]
+# date_sources = ["filename", "front_matter", "heading", "mtime"]  # Priority for "import files"
+# conflict = "skip"  # skip, append or rename when an entry already exists
+
+[encryption]
+# enabled = false                                 # Write new entries encrypted as .md.age
+# identity_file = "~/.config/jnal/identity.txt"   # Create one with "jnal keygen"
+# passphrase_env = "JNAL_PASSPHRASE"              # Passphrase of a protected identity file
+
+[git]
+# auto_commit = false                  # Commit entries changed by new, edit, attach, import, ...
+# commit_message = "entry: {{ .Date }}"
+# sync_message = "sync: {{ .Date }}"   # Message for changes committed by "jnal sync"
+# remote = "origin"
+# branch = ""                          # Default: the current branch
 `
 
 func newInitCommand() *cobra.Command {
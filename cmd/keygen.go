@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/crypt"
+	"github.com/longkey1/jnal/internal/term"
+	"github.com/spf13/cobra"
+)
+
+func newKeygenCommand() *cobra.Command {
+	var protect bool
+
+	cmd := &cobra.Command{
+		Use:   "keygen <identity-file>",
+		Short: "Generate an identity file for encrypted entries",
+		Long: `Generate an age identity file for encrypting entries and print its public key.
+With --passphrase the file is protected with a passphrase, which is asked for
+when encrypted entries are read (or taken from $JNAL_PASSPHRASE).
+
+The file is compatible with age-keygen; keep a backup, since encrypted
+entries can't be recovered without it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("identity file already exists: %s", path)
+			}
+
+			passphrase := ""
+			if protect {
+				var err error
+				passphrase, err = term.ReadPassword("Passphrase: ")
+				if err != nil {
+					return err
+				}
+				confirmation, err := term.ReadPassword("Confirm passphrase: ")
+				if err != nil {
+					return err
+				}
+				if passphrase == "" || passphrase != confirmation {
+					return fmt.Errorf("passphrases are empty or don't match")
+				}
+			}
+
+			data, publicKey, err := crypt.GenerateIdentityFile(passphrase)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), config.DirPermission); err != nil {
+				return fmt.Errorf("creating directory: %w", err)
+			}
+			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+			if err != nil {
+				return fmt.Errorf("creating identity file: %w", err)
+			}
+			if _, err := f.Write(data); err != nil {
+				f.Close()
+				return fmt.Errorf("writing identity file: %w", err)
+			}
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("writing identity file: %w", err)
+			}
+
+			fmt.Printf("Created identity file at %s\n", path)
+			fmt.Printf("Public key: %s\n", publicKey)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&protect, "passphrase", "p", false, "Protect the identity file with a passphrase")
+
+	return cmd
+}
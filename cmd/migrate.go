@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/term"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCommand(app **jnal.App) *cobra.Command {
+	var (
+		toFormat string
+		dryRun   bool
+		yes      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Move entries to match a new path format",
+		Long: `Move entries written according to the current path_format to the paths of
+a new format, along with their attachment directories.
+
+The plan is shown before anything is moved. Migration is refused if any target
+path is taken, and moves already done are rolled back if one fails. Files tracked
+in a git repository are moved with git mv. Update path_format in the config
+file afterwards.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			journal := (*app).Journal()
+
+			plan, err := journal.PlanMigration(toFormat)
+			if err != nil {
+				return fmt.Errorf("planning migration: %w", err)
+			}
+
+			printMigrationPlan(plan, journal.GetBaseDir())
+
+			if len(plan.Collisions) > 0 {
+				return fmt.Errorf("%d target paths are taken, nothing was moved", len(plan.Collisions))
+			}
+			if len(plan.Moves) == 0 || dryRun {
+				return nil
+			}
+
+			if !yes {
+				if !term.IsTerminal(os.Stdin) {
+					return fmt.Errorf("refusing to migrate without confirmation (use --yes)")
+				}
+				if !confirm(fmt.Sprintf("Move %d files?", len(plan.Moves))) {
+					fmt.Println("Aborted")
+					return nil
+				}
+			}
+
+			if err := journal.Migrate(plan); err != nil {
+				return fmt.Errorf("migrating: %w", err)
+			}
+
+			warnCommit(journal.CommitChanges("migrate: path format " + plan.Format))
+
+			fmt.Printf("Moved %d files\n", len(plan.Moves))
+			fmt.Printf("Remember to set path_format = %q in the config file\n", plan.Format)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&toFormat, "to-format", "", "New path format (e.g. 2006/01/2006-01-02.md)")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Show the plan without moving anything")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Don't ask for confirmation")
+	_ = cmd.MarkFlagRequired("to-format")
+
+	return cmd
+}
+
+// printMigrationPlan prints the moves of a migration relative to the base directory
+func printMigrationPlan(plan *jnal.MigrationPlan, baseDir string) {
+	rel := func(path string) string {
+		if r, err := filepath.Rel(baseDir, path); err == nil {
+			return r
+		}
+		return path
+	}
+
+	for _, m := range plan.Moves {
+		fmt.Printf("%s -> %s\n", rel(m.From), rel(m.To))
+	}
+	for _, path := range plan.Unmatched {
+		fmt.Printf("Not moved: %s (doesn't match the current path format)\n", rel(path))
+	}
+	for _, c := range plan.Collisions {
+		fmt.Printf("Collision: %s\n", c)
+	}
+	for _, w := range plan.Warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	fmt.Printf("%d to move, %d unchanged, %d not matched\n", len(plan.Moves), plan.Unchanged, len(plan.Unmatched))
+}
+
+// confirm asks a yes/no question on the terminal and reports whether the answer was yes
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -28,6 +28,8 @@ func newNewCommand(app **jnal.App) *cobra.Command {
 				return fmt.Errorf("creating entry: %w", err)
 			}
 
+			warnCommit((*app).Journal().CommitEntry(targetDate))
+
 			fmt.Println(entryPath)
 
 			return nil
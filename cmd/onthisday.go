@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newOnThisDayCommand(app **jnal.App) *cobra.Command {
+	var (
+		date     string
+		full     bool
+		length   int
+		weekAgo  bool
+		monthAgo bool
+		yearAgo  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "onthisday",
+		Short: "Show entries from this day in previous years",
+		Long: `Show journal entries written on the same month and day in previous years,
+and optionally on the same weekday a week, four weeks or a year ago.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetDate, err := util.Parse(date)
+			if err != nil {
+				return fmt.Errorf("invalid date %q: %w", date, err)
+			}
+
+			memories, err := (*app).Journal().OnThisDay(targetDate, jnal.OnThisDayOptions{
+				WeekAgo:  weekAgo,
+				MonthAgo: monthAgo,
+				YearAgo:  yearAgo,
+			})
+			if err != nil {
+				return fmt.Errorf("finding entries: %w", err)
+			}
+
+			if len(memories) == 0 {
+				fmt.Printf("No entries on this day (%s) in previous years.\n", util.Format(targetDate))
+				return nil
+			}
+
+			for i, m := range memories {
+				data, err := (*app).Journal().ReadEntry(m.Entry.Path)
+				if err != nil {
+					return fmt.Errorf("reading entry %s: %w", m.Entry.Path, err)
+				}
+
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("%s (%s)\n", util.Format(m.Entry.Date), m.Label)
+				if full {
+					fmt.Println(strings.TrimRight(string(data), "\n"))
+				} else {
+					fmt.Printf("  %s\n", jnal.Excerpt(string(data), length))
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&date, "date", "d",
+		util.Format(util.Today()),
+		"Target date (format: yyyy-mm-dd)")
+	cmd.Flags().BoolVarP(&full, "full", "f", false, "Print the full entries instead of excerpts")
+	cmd.Flags().IntVarP(&length, "length", "n", 200, "Maximum length of excerpts in characters")
+	cmd.Flags().BoolVar(&weekAgo, "week", false, "Also show the entry from the same weekday a week ago")
+	cmd.Flags().BoolVar(&monthAgo, "month", false, "Also show the entry from the same weekday four weeks ago")
+	cmd.Flags().BoolVar(&yearAgo, "year", false, "Also show the entry from the same ISO week and weekday a year ago")
+
+	return cmd
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/term"
 	"github.com/spf13/cobra"
 )
 
@@ -28,6 +29,11 @@ func NewRootCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("initializing app: %w", err)
 			}
+
+			// Asked only when an encrypted entry is read with a protected identity file
+			app.Journal().SetPassphrasePrompt(func() (string, error) {
+				return term.ReadPassword("Identity file passphrase: ")
+			})
 			return nil
 		},
 	}
@@ -37,9 +43,23 @@ func NewRootCommand() *cobra.Command {
 
 	// Add subcommands
 	cmd.AddCommand(newNewCommand(&app))
+	cmd.AddCommand(newEditCommand(&app))
 	cmd.AddCommand(newBuildCommand(&app))
 	cmd.AddCommand(newServeCommand(&app))
+	cmd.AddCommand(newThemeCommand(&app))
 	cmd.AddCommand(newPathCommand(&app))
+	cmd.AddCommand(newAttachCommand(&app))
+	cmd.AddCommand(newStatsCommand(&app))
+	cmd.AddCommand(newCalCommand(&app))
+	cmd.AddCommand(newOnThisDayCommand(&app))
+	cmd.AddCommand(newShowCommand(&app))
+	cmd.AddCommand(newExportCommand(&app))
+	cmd.AddCommand(newImportCommand(&app))
+	cmd.AddCommand(newMigrateCommand(&app))
+	cmd.AddCommand(newSyncCommand(&app))
+	cmd.AddCommand(newEncryptCommand(&app))
+	cmd.AddCommand(newDecryptCommand(&app))
+	cmd.AddCommand(newKeygenCommand())
 	cmd.AddCommand(newInitCommand())
 	cmd.AddCommand(newVersionCommand())
 
@@ -51,6 +71,7 @@ func shouldSkipConfig(cmd *cobra.Command) bool {
 	skipCommands := map[string]bool{
 		"init":       true,
 		"version":    true,
+		"keygen":     true,
 		"help":       true,
 		"completion": true,
 	}
@@ -18,6 +18,9 @@ func newServeCommand(app **jnal.App) *cobra.Command {
 		port       int
 		sort       string
 		liveReload bool
+		noCache    bool
+		jobs       int
+		css        string
 	)
 
 	cmd := &cobra.Command{
@@ -36,6 +39,16 @@ The server watches for file changes and automatically reloads content.`,
 			if cmd.Flags().Changed("sort") {
 				cfg.Build.Sort = sort
 			}
+			if noCache {
+				cache := false
+				cfg.Build.Cache = &cache
+			}
+			if cmd.Flags().Changed("jobs") {
+				cfg.Build.Jobs = jobs
+			}
+			if cmd.Flags().Changed("css") {
+				cfg.Build.CSS = cssFlag(css)
+			}
 
 			// Validate config
 			if err := cfg.Validate(); err != nil {
@@ -66,6 +79,9 @@ The server watches for file changes and automatically reloads content.`,
 	cmd.Flags().IntVarP(&port, "port", "p", config.DefaultPort, "Port to listen on")
 	cmd.Flags().StringVarP(&sort, "sort", "s", config.DefaultSort, "Sort order: desc (newest first), asc (oldest first)")
 	cmd.Flags().BoolVarP(&liveReload, "live-reload", "l", false, "Enable live reload on file changes")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Disable the render cache")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 0, "Number of entries rendered concurrently (default: GOMAXPROCS)")
+	cmd.Flags().StringVar(&css, "css", "", cssFlagUsage)
 
 	return cmd
 }
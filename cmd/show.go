@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/term"
+	"github.com/longkey1/jnal/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func newShowCommand(app **jnal.App) *cobra.Command {
+	var (
+		date    string
+		from    string
+		to      string
+		raw     bool
+		noPager bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print journal entries",
+		Long: `Print one or more journal entries with date separators, either as raw Markdown
+or rendered for the terminal. Output goes through $PAGER when interactive.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if date != "" && (from != "" || to != "") {
+				return fmt.Errorf("--date cannot be used together with --from or --to")
+			}
+
+			var dateRange util.DateRange
+			var err error
+			switch {
+			case from != "" || to != "":
+				dateRange, err = util.ParseRange(from, to)
+			case date != "":
+				dateRange, err = util.ParseRange(date, date)
+			default:
+				today := util.Format(util.Today())
+				dateRange, err = util.ParseRange(today, today)
+			}
+			if err != nil {
+				return err
+			}
+
+			entries, err := (*app).Journal().ListEntries()
+			if err != nil {
+				return fmt.Errorf("listing entries: %w", err)
+			}
+			entries = entries.Filter(dateRange)
+			entries.SortByDateAsc()
+
+			if len(entries) == 0 {
+				return fmt.Errorf("no entries found")
+			}
+
+			interactive := term.IsTerminal(os.Stdout)
+			color := interactive && os.Getenv("NO_COLOR") == ""
+			width := term.Width(os.Stdout)
+
+			var sb strings.Builder
+			for i, e := range entries {
+				data, err := (*app).Journal().ReadEntry(e.Path)
+				if err != nil {
+					return fmt.Errorf("reading entry %s: %w", e.Path, err)
+				}
+
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+				sb.WriteString(term.Separator(e.Date.Format("2006-01-02 (Monday)"), width, color))
+				sb.WriteString("\n\n")
+				if raw {
+					sb.WriteString(strings.TrimRight(string(data), "\n") + "\n")
+				} else {
+					sb.WriteString(term.RenderMarkdown([]byte(jnal.StripFrontMatter(string(data))), width, color))
+				}
+			}
+
+			if noPager || !interactive {
+				_, err := fmt.Print(sb.String())
+				return err
+			}
+			return term.Page(os.Stdout, sb.String())
+		},
+	}
+
+	cmd.Flags().StringVarP(&date, "date", "d", "", "Date of the entry (format: yyyy-mm-dd, default: today)")
+	cmd.Flags().StringVar(&from, "from", "", "Show entries from this date (format: yyyy-mm-dd)")
+	cmd.Flags().StringVar(&to, "to", "", "Show entries up to this date (format: yyyy-mm-dd)")
+	cmd.Flags().BoolVarP(&raw, "raw", "r", false, "Print raw Markdown")
+	cmd.Flags().BoolVar(&noPager, "no-pager", false, "Do not pipe output through $PAGER")
+
+	return cmd
+}
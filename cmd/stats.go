@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/term"
+	"github.com/longkey1/jnal/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// Stats output formats
+const (
+	statsFormatText    = "text"
+	statsFormatJSON    = "json"
+	statsFormatHeatmap = "heatmap"
+)
+
+func newStatsCommand(app **jnal.App) *cobra.Command {
+	var (
+		format string
+		weeks  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show journaling statistics",
+		Long: `Show journaling statistics: entries, words, streaks, missed days,
+busiest weekday and the year-over-year trend.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			today := util.Today()
+			stats, err := (*app).Journal().Stats(today)
+			if err != nil {
+				return fmt.Errorf("computing stats: %w", err)
+			}
+
+			switch format {
+			case statsFormatText:
+				return printStats(os.Stdout, stats)
+			case statsFormatJSON:
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(stats)
+			case statsFormatHeatmap:
+				fmt.Print(term.Heatmap(stats.Days, today, weeks))
+				return nil
+			default:
+				return fmt.Errorf("invalid format: %s (must be one of: text, json, heatmap)", format)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", statsFormatText, "Output format: text, json, heatmap")
+	cmd.Flags().IntVarP(&weeks, "weeks", "w", 53, "Number of weeks shown in the heatmap")
+
+	return cmd
+}
+
+// printStats prints the statistics as text
+func printStats(out io.Writer, stats *jnal.Stats) error {
+	if stats.TotalEntries == 0 {
+		_, err := fmt.Fprintln(out, "No journal entries found.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Entries:\t%d\n", stats.TotalEntries)
+	fmt.Fprintf(w, "Words:\t%d (%.1f per entry)\n", stats.TotalWords, stats.WordsPerEntry)
+	fmt.Fprintf(w, "First entry:\t%s\n", stats.FirstDate)
+	fmt.Fprintf(w, "Last entry:\t%s\n", stats.LastDate)
+	fmt.Fprintf(w, "Longest streak:\t%s\n", formatStreak(stats.LongestStreak))
+	fmt.Fprintf(w, "Current streak:\t%s\n", formatStreak(stats.CurrentStreak))
+	fmt.Fprintf(w, "Missed days:\t%d\n", stats.MissedDays)
+	fmt.Fprintf(w, "Busiest weekday:\t%s\n", stats.BusiestWeekday)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	entries := make([]int, len(stats.Months))
+	words := make([]int, len(stats.Months))
+	for i, m := range stats.Months {
+		entries[i] = m.Entries
+		words[i] = m.Words
+	}
+	first, last := stats.Months[0].Period, stats.Months[len(stats.Months)-1].Period
+	fmt.Fprintf(out, "\nMonthly (%s to %s)\n", first, last)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Entries\t%s\n", term.Sparkline(entries))
+	fmt.Fprintf(w, "Words\t%s\n", term.Sparkline(words))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\nYearly")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "Year\tEntries\tWords\tWords/entry\tChange\t\n")
+	for _, y := range stats.Years {
+		change := "-"
+		if y.Change != nil {
+			change = fmt.Sprintf("%+.1f%%", *y.Change)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%s\t\n", y.Period, y.Entries, y.Words, y.WordsPerEntry, change)
+	}
+	return w.Flush()
+}
+
+// formatStreak formats a streak for text output
+func formatStreak(s jnal.Streak) string {
+	switch s.Days {
+	case 0:
+		return "0 days"
+	case 1:
+		return fmt.Sprintf("1 day (%s)", s.Start)
+	default:
+		return fmt.Sprintf("%d days (%s to %s)", s.Days, s.Start, s.End)
+	}
+}
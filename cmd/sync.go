@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/longkey1/jnal/internal/git"
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/spf13/cobra"
+)
+
+func newSyncCommand(app **jnal.App) *cobra.Command {
+	var opts git.SyncOptions
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Commit, pull and push the journal repository",
+		Long: `Commit all changes in the base directory, pull from the remote with rebase
+and push the result.
+
+Entries changed both locally and on the remote are merged by keeping the lines
+of both versions, so nothing written on either side is lost; review the listed
+entries afterwards. If other files conflict, the rebase is aborted and the local
+commit is kept for you to resolve by hand.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := (*app).Journal().Sync(opts)
+			var conflict *git.ConflictError
+			if errors.As(err, &conflict) {
+				for _, f := range conflict.Files {
+					fmt.Printf("Conflict: %s\n", f)
+				}
+				return fmt.Errorf("sync stopped: resolve the conflicts with git and run sync again")
+			}
+			if err != nil {
+				return fmt.Errorf("syncing: %w", err)
+			}
+
+			if result.Committed {
+				fmt.Println("Committed local changes")
+			}
+			for _, f := range result.Merged {
+				fmt.Printf("Merged: %s (changed on both sides, review it)\n", f)
+			}
+			fmt.Printf("%d pulled, %d pushed\n", result.Pulled, result.Pushed)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Remote, "remote", "", "Remote to sync with (default from config)")
+	cmd.Flags().StringVar(&opts.Branch, "branch", "", "Remote branch (default from config, or the current branch)")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Commit message for local changes (default from config)")
+
+	return cmd
+}
+
+// warnCommit reports a failed auto-commit without failing the command, since the
+// entry itself has been written
+func warnCommit(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: auto-commit failed: %v\n", err)
+	}
+}
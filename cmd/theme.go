@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/server"
+	"github.com/spf13/cobra"
+)
+
+func newThemeCommand(app **jnal.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "theme",
+		Short: "Manage HTML templates",
+		Long: `Manage the templates of the HTML output. Templates in build.template_dir, or in
+the themes/<name> directory next to the config file for build.theme, override
+the built-in templates file by file.`,
+	}
+
+	cmd.AddCommand(newThemeEjectCommand(app))
+
+	return cmd
+}
+
+func newThemeEjectCommand(app **jnal.App) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "eject [dir]",
+		Short: "Copy the built-in templates to a directory",
+		Long: `Copy the built-in templates to a directory as a starting point for custom
+templates. The directory defaults to build.template_dir, or templates/ next to
+the config file. Templates you don't change can be deleted again, so that they
+keep following the built-in ones.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := (*app).Config()
+
+			// Relative template_dir paths are relative to the config file
+			dir, setting := cfg.Build.TemplateDir, "templates"
+			if len(args) > 0 {
+				dir = args[0]
+				if abs, err := filepath.Abs(dir); err == nil {
+					setting = abs
+				}
+			} else if dir == "" {
+				dir = filepath.Join(cfg.Dir, setting)
+			}
+
+			paths, err := server.EjectTemplates(dir, force)
+			if err != nil {
+				return fmt.Errorf("ejecting templates: %w", err)
+			}
+			for _, p := range paths {
+				fmt.Printf("Created %s\n", p)
+			}
+
+			if cfg.Build.TemplateDir == "" {
+				fmt.Printf("Set template_dir = %q under [build] to use them\n", setting)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite existing templates")
+
+	return cmd
+}
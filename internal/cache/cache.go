@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/version"
+)
+
+// unsafeChars matches characters not allowed in cache directory names
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// Cache is an on-disk key/value store. A nil *Cache is valid and caches nothing.
+type Cache struct {
+	dir string
+}
+
+// DefaultDir returns the default cache directory ($XDG_CACHE_HOME/jnal on Linux)
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("getting cache directory: %w", err)
+	}
+	return filepath.Join(dir, "jnal"), nil
+}
+
+// Open opens the named cache under the default cache directory.
+// Caches written by other jnal versions are removed.
+func Open(name string) (*Cache, error) {
+	baseDir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return OpenDir(filepath.Join(baseDir, name))
+}
+
+// OpenShared opens the named cache under the default cache directory. Unlike Open, it is
+// shared by all jnal versions, for data that doesn't depend on the version such as
+// downloaded files, which must outlive upgrades to work offline.
+func OpenShared(name string) (*Cache, error) {
+	baseDir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(baseDir, name)
+	if err := os.MkdirAll(dir, config.DirPermission); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// OpenDir opens a cache in the given directory.
+// Caches written by other jnal versions are removed.
+func OpenDir(dir string) (*Cache, error) {
+	current := versionDirName()
+
+	if err := os.MkdirAll(filepath.Join(dir, current), config.DirPermission); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	// Invalidate caches of other versions
+	dirs, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading cache directory: %w", err)
+	}
+	for _, d := range dirs {
+		if d.IsDir() && d.Name() != current {
+			os.RemoveAll(filepath.Join(dir, d.Name()))
+		}
+	}
+
+	return &Cache{dir: filepath.Join(dir, current)}, nil
+}
+
+// Key returns a cache key derived from the given parts
+func Key(parts ...[]byte) string {
+	h := sha256.New()
+	for _, p := range parts {
+		// Length prefix keeps ("ab", "c") and ("a", "bc") apart
+		fmt.Fprintf(h, "%d:", len(p))
+		h.Write(p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the cached data for the key
+func (c *Cache) Get(key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// GetWithAge returns the cached data for the key and how long ago it was stored
+func (c *Cache) GetWithAge(key string) ([]byte, time.Duration, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, 0, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, false
+	}
+	return data, time.Since(info.ModTime()), true
+}
+
+// Put stores data for the key
+func (c *Cache) Put(key string, data []byte) error {
+	if c == nil {
+		return nil
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), config.DirPermission); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see partial data
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating cache file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the file path for the key, sharded by its first two characters
+func (c *Cache) path(key string) string {
+	if len(key) < 2 {
+		return filepath.Join(c.dir, key)
+	}
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+// versionDirName returns the directory name for the running jnal version
+func versionDirName() string {
+	return unsafeChars.ReplaceAllString(version.Version+"-"+version.CommitSHA, "_")
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/longkey1/jnal/internal/version"
+)
+
+func TestCache_GetPut(t *testing.T) {
+	c, err := OpenDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenDir() error = %v", err)
+	}
+
+	key := Key([]byte("content"), []byte("settings"))
+	if _, ok := c.Get(key); ok {
+		t.Fatal("Get() hit on empty cache")
+	}
+
+	if err := c.Put(key, []byte("<p>html</p>")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	got, ok := c.Get(key)
+	if !ok || string(got) != "<p>html</p>" {
+		t.Errorf("Get() = %q, %v, want %q, true", got, ok, "<p>html</p>")
+	}
+
+	got, age, ok := c.GetWithAge(key)
+	if !ok || string(got) != "<p>html</p>" || age < 0 || age > time.Minute {
+		t.Errorf("GetWithAge() = %q, %v, %v", got, age, ok)
+	}
+}
+
+func TestCache_Nil(t *testing.T) {
+	var c *Cache
+	if err := c.Put("key", []byte("data")); err != nil {
+		t.Errorf("Put() on nil cache error = %v", err)
+	}
+	if _, ok := c.Get("key"); ok {
+		t.Error("Get() on nil cache hit")
+	}
+}
+
+func TestKey(t *testing.T) {
+	if Key([]byte("ab"), []byte("c")) == Key([]byte("a"), []byte("bc")) {
+		t.Error("Key() collides for different parts")
+	}
+	if Key([]byte("a")) != Key([]byte("a")) {
+		t.Error("Key() is not deterministic")
+	}
+}
+
+func TestOpenDir_RemovesOtherVersions(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "v0.0.1-deadbeef")
+	if err := os.MkdirAll(stale, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenDir(dir); err != nil {
+		t.Fatalf("OpenDir() error = %v", err)
+	}
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale version cache still exists: %v", err)
+	}
+}
+
+func TestOpenShared_KeptAcrossVersions(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer func(v string) { version.Version = v }(version.Version)
+
+	c, err := OpenShared("downloads")
+	if err != nil {
+		t.Fatalf("OpenShared() error = %v", err)
+	}
+	if err := c.Put("key", []byte("data")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	// Versioned caches opened by the new version don't remove shared data
+	version.Version = "v99.0.0"
+	if _, err := Open("render"); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	c, err = OpenShared("downloads")
+	if err != nil {
+		t.Fatalf("OpenShared() error = %v", err)
+	}
+	if data, ok := c.Get("key"); !ok || string(data) != "data" {
+		t.Errorf("Get() = %q, %v after upgrade, want %q", data, ok, "data")
+	}
+}
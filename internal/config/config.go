@@ -3,6 +3,14 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	homedir "github.com/mitchellh/go-homedir"
 )
 
 // Permission constants
@@ -13,9 +21,39 @@ const (
 
 // Default values
 const (
-	DefaultPort         = 8080
-	DefaultSort         = "desc"
-	DefaultHeadingShift = 4
+	DefaultPort           = 8080
+	DefaultSort           = "desc"
+	DefaultHeadingShift   = 4
+	DefaultTOCMinHeadings = 3
+	DefaultAttachDir      = "attachments/{{ .Name }}"
+	DefaultCommitMsg      = "entry: {{ .Date }}"
+	DefaultSyncMsg        = "sync: {{ .Date }}"
+	DefaultRemote         = "origin"
+	DefaultPassphrase     = "JNAL_PASSPHRASE"
+)
+
+// Week start options
+const (
+	WeekStartSunday = "sunday"
+	WeekStartMonday = "monday"
+)
+
+// Date sources for importing files, in the default order of priority
+const (
+	DateSourceFilename    = "filename"
+	DateSourceFrontMatter = "front_matter"
+	DateSourceHeading     = "heading"
+	DateSourceMtime       = "mtime"
+)
+
+// DefaultDateSources is the default priority of date sources for importing files
+var DefaultDateSources = []string{DateSourceFilename, DateSourceFrontMatter, DateSourceHeading, DateSourceMtime}
+
+// Conflict options for importing files when an entry already exists
+const (
+	ConflictSkip   = "skip"
+	ConflictAppend = "append"
+	ConflictRename = "rename"
 )
 
 // Sort options
@@ -26,10 +64,18 @@ const (
 
 // Config represents the application configuration
 type Config struct {
-	Common CommonConfig `mapstructure:"common"`
-	New    NewConfig    `mapstructure:"new"`
-	Build  BuildConfig  `mapstructure:"build"`
-	Serve  ServeConfig  `mapstructure:"serve"`
+	Common     CommonConfig     `mapstructure:"common"`
+	New        NewConfig        `mapstructure:"new"`
+	Build      BuildConfig      `mapstructure:"build"`
+	Serve      ServeConfig      `mapstructure:"serve"`
+	Attach     AttachConfig     `mapstructure:"attach"`
+	Cal        CalConfig        `mapstructure:"cal"`
+	Import     ImportConfig     `mapstructure:"import"`
+	Git        GitConfig        `mapstructure:"git"`
+	Encryption EncryptionConfig `mapstructure:"encryption"`
+
+	// Dir is the directory of the loaded config file, which relative paths are resolved against
+	Dir string `mapstructure:"-"`
 }
 
 // CommonConfig represents common configuration shared across commands
@@ -53,6 +99,23 @@ type BuildConfig struct {
 	HardWraps       *bool  `mapstructure:"hard_wraps"`
 	Linkify         *bool  `mapstructure:"linkify"`
 	LinkTargetBlank *bool  `mapstructure:"link_target_blank"`
+	Cache           *bool  `mapstructure:"cache"`
+	Jobs            int    `mapstructure:"jobs"`
+	// CSSDark replaces css when the dark color scheme is used
+	CSSDark string `mapstructure:"css_dark"`
+	// DarkModeToggle shows a light/dark switch on pages whose stylesheets support both
+	DarkModeToggle *bool `mapstructure:"dark_mode_toggle"`
+	// Drafts includes entries marked draft: true in builds
+	Drafts bool `mapstructure:"drafts"`
+	// Future includes entries with a publish_date after today in builds
+	Future bool `mapstructure:"future"`
+	// TemplateDir contains templates overriding the built-in ones file by file
+	TemplateDir string `mapstructure:"template_dir"`
+	// Theme is the name of a directory of templates under themes/ in the config directory
+	Theme string `mapstructure:"theme"`
+	// TOC adds a table of contents at the top of entries with at least TOCMinHeadings headings
+	TOC            bool `mapstructure:"toc"`
+	TOCMinHeadings int  `mapstructure:"toc_min_headings"`
 }
 
 // ServeConfig represents the serve command configuration (content delivery)
@@ -60,6 +123,48 @@ type ServeConfig struct {
 	Port int `mapstructure:"port"`
 }
 
+// AttachConfig represents the attach command configuration
+type AttachConfig struct {
+	// Directory is a template for the attachments directory, relative to the entry's directory
+	Directory string `mapstructure:"directory"`
+}
+
+// CalConfig represents the cal command configuration
+type CalConfig struct {
+	WeekStart   string `mapstructure:"week_start"`
+	WeekNumbers bool   `mapstructure:"week_numbers"`
+}
+
+// ImportConfig represents the import command configuration
+type ImportConfig struct {
+	// DateSources lists where the date of an imported file is taken from, in order of priority
+	DateSources []string `mapstructure:"date_sources"`
+	Conflict    string   `mapstructure:"conflict"`
+}
+
+// GitConfig represents the git integration configuration
+type GitConfig struct {
+	// AutoCommit commits entries after commands that change them
+	AutoCommit bool `mapstructure:"auto_commit"`
+	// CommitMessage is a template for auto-commit messages
+	CommitMessage string `mapstructure:"commit_message"`
+	// SyncMessage is a template for the message of changes committed by sync
+	SyncMessage string `mapstructure:"sync_message"`
+	Remote      string `mapstructure:"remote"`
+	// Branch is the remote branch to sync with (default: the current branch)
+	Branch string `mapstructure:"branch"`
+}
+
+// EncryptionConfig represents the configuration of entries encrypted at rest
+type EncryptionConfig struct {
+	// Enabled writes new entries encrypted as .md.age files
+	Enabled bool `mapstructure:"enabled"`
+	// IdentityFile is an age identity file, optionally protected with a passphrase
+	IdentityFile string `mapstructure:"identity_file"`
+	// PassphraseEnv names the environment variable holding the identity file passphrase
+	PassphraseEnv string `mapstructure:"passphrase_env"`
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	if err := c.Common.Validate(); err != nil {
@@ -74,6 +179,29 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("serve config: %w", err)
 	}
 
+	if err := c.Attach.Validate(); err != nil {
+		return fmt.Errorf("attach config: %w", err)
+	}
+	if err := c.Attach.validateWithin(c.Common); err != nil {
+		return fmt.Errorf("attach config: %w", err)
+	}
+
+	if err := c.Cal.Validate(); err != nil {
+		return fmt.Errorf("cal config: %w", err)
+	}
+
+	if err := c.Import.Validate(); err != nil {
+		return fmt.Errorf("import config: %w", err)
+	}
+
+	if err := c.Git.Validate(); err != nil {
+		return fmt.Errorf("git config: %w", err)
+	}
+
+	if err := c.Encryption.Validate(); err != nil {
+		return fmt.Errorf("encryption config: %w", err)
+	}
+
 	return nil
 }
 
@@ -93,6 +221,14 @@ func (b *BuildConfig) Validate() error {
 		return fmt.Errorf("invalid sort: %s (must be one of: desc, asc)", b.Sort)
 	}
 
+	if b.Jobs < 0 {
+		return fmt.Errorf("jobs must not be negative")
+	}
+
+	if b.Theme != "" && (b.Theme == "." || b.Theme == ".." || strings.ContainsAny(b.Theme, `/\`)) {
+		return fmt.Errorf("invalid theme: %s (must be a directory name under themes/)", b.Theme)
+	}
+
 	return nil
 }
 
@@ -105,12 +241,107 @@ func (s *ServeConfig) Validate() error {
 	return nil
 }
 
+// Validate validates the attach configuration
+func (a *AttachConfig) Validate() error {
+	if filepath.IsAbs(a.Directory) {
+		return fmt.Errorf("directory must be relative to the entry directory: %s", a.Directory)
+	}
+
+	return nil
+}
+
+// validateWithin checks that the directory template, rendered for a sample entry stored
+// according to the path format, stays inside the base directory. Attachments outside
+// of it are never served or copied by builds.
+func (a *AttachConfig) validateWithin(common CommonConfig) error {
+	tmpl, err := template.New("").Parse(a.Directory)
+	if err != nil {
+		return fmt.Errorf("invalid directory: %w", err)
+	}
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	entryPath := date.Format(common.PathFormat)
+	name := strings.TrimSuffix(filepath.Base(entryPath), filepath.Ext(entryPath))
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, map[string]any{"Name": name, "Date": date.Format(common.DateFormat)}); err != nil {
+		return fmt.Errorf("invalid directory: %w", err)
+	}
+
+	dir := filepath.Join(filepath.Dir(entryPath), filepath.FromSlash(buf.String()))
+	if !filepath.IsLocal(dir) {
+		return fmt.Errorf("directory must be inside base_directory: %s", a.Directory)
+	}
+
+	return nil
+}
+
+// Validate validates the cal configuration
+func (c *CalConfig) Validate() error {
+	validWeekStarts := map[string]bool{WeekStartSunday: true, WeekStartMonday: true}
+	if c.WeekStart != "" && !validWeekStarts[c.WeekStart] {
+		return fmt.Errorf("invalid week_start: %s (must be one of: sunday, monday)", c.WeekStart)
+	}
+
+	return nil
+}
+
+// Validate validates the import configuration
+func (i *ImportConfig) Validate() error {
+	seen := make(map[string]bool)
+	for _, source := range i.DateSources {
+		if !slices.Contains(DefaultDateSources, source) {
+			return fmt.Errorf("invalid date source: %s (must be one of: filename, front_matter, heading, mtime)", source)
+		}
+		if seen[source] {
+			return fmt.Errorf("duplicate date source: %s", source)
+		}
+		seen[source] = true
+	}
+
+	validConflicts := map[string]bool{ConflictSkip: true, ConflictAppend: true, ConflictRename: true}
+	if i.Conflict != "" && !validConflicts[i.Conflict] {
+		return fmt.Errorf("invalid conflict: %s (must be one of: skip, append, rename)", i.Conflict)
+	}
+
+	return nil
+}
+
+// Validate validates the git configuration
+func (g *GitConfig) Validate() error {
+	if _, err := template.New("").Parse(g.CommitMessage); err != nil {
+		return fmt.Errorf("invalid commit_message: %w", err)
+	}
+	if _, err := template.New("").Parse(g.SyncMessage); err != nil {
+		return fmt.Errorf("invalid sync_message: %w", err)
+	}
+	if strings.HasPrefix(g.Remote, "-") || strings.HasPrefix(g.Branch, "-") {
+		return fmt.Errorf("remote and branch must not start with '-'")
+	}
+
+	return nil
+}
+
+// Validate validates the encryption configuration
+func (e *EncryptionConfig) Validate() error {
+	if e.Enabled && e.IdentityFile == "" {
+		return fmt.Errorf("identity_file is required when encryption is enabled")
+	}
+
+	return nil
+}
+
 // SetDefaults sets default values for the configuration
 func (c *Config) SetDefaults() {
 	c.Common.SetDefaults()
 	c.New.SetDefaults()
 	c.Build.SetDefaults()
 	c.Serve.SetDefaults()
+	c.Attach.SetDefaults()
+	c.Cal.SetDefaults()
+	c.Import.SetDefaults()
+	c.Git.SetDefaults()
+	c.Encryption.SetDefaults()
 }
 
 // SetDefaults sets default values for the common configuration
@@ -152,6 +383,43 @@ func (b *BuildConfig) SetDefaults() {
 		defaultLinkTargetBlank := true
 		b.LinkTargetBlank = &defaultLinkTargetBlank
 	}
+	if b.Cache == nil {
+		defaultCache := true
+		b.Cache = &defaultCache
+	}
+	if b.DarkModeToggle == nil {
+		defaultDarkModeToggle := true
+		b.DarkModeToggle = &defaultDarkModeToggle
+	}
+	if b.TOCMinHeadings <= 0 {
+		b.TOCMinHeadings = DefaultTOCMinHeadings
+	}
+	if expanded, err := homedir.Expand(b.TemplateDir); err == nil {
+		b.TemplateDir = expanded
+	}
+}
+
+// ResolvePaths makes relative paths in the build configuration relative to dir
+func (b *BuildConfig) ResolvePaths(dir string) {
+	if b.TemplateDir != "" && !filepath.IsAbs(b.TemplateDir) {
+		b.TemplateDir = filepath.Join(dir, b.TemplateDir)
+	}
+}
+
+// ThemeDir returns the directory of the theme, or "" if no theme is set.
+// Themes are located under themes/ in the directory of the config file.
+func (c *Config) ThemeDir() (string, error) {
+	if c.Build.Theme == "" {
+		return "", nil
+	}
+	dir := c.Dir
+	if dir == "" {
+		var err error
+		if dir, err = DefaultConfigDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, "themes", c.Build.Theme), nil
 }
 
 // GetHeadingShift returns the heading shift value (0 means disabled)
@@ -186,9 +454,96 @@ func (b *BuildConfig) GetLinkTargetBlank() bool {
 	return *b.LinkTargetBlank
 }
 
+// GetCache returns the render cache setting (default: true)
+func (b *BuildConfig) GetCache() bool {
+	if b.Cache == nil {
+		return true
+	}
+	return *b.Cache
+}
+
+// GetDarkModeToggle returns whether pages show a light/dark switch (default: true)
+func (b *BuildConfig) GetDarkModeToggle() bool {
+	if b.DarkModeToggle == nil {
+		return true
+	}
+	return *b.DarkModeToggle
+}
+
+// GetTOCMinHeadings returns the number of headings an entry needs for a table of contents (default: 3)
+func (b *BuildConfig) GetTOCMinHeadings() int {
+	if b.TOCMinHeadings <= 0 {
+		return DefaultTOCMinHeadings
+	}
+	return b.TOCMinHeadings
+}
+
+// GetJobs returns the number of entries rendered concurrently (default: GOMAXPROCS)
+func (b *BuildConfig) GetJobs() int {
+	if b.Jobs <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return b.Jobs
+}
+
 // SetDefaults sets default values for the serve configuration
 func (s *ServeConfig) SetDefaults() {
 	if s.Port == 0 {
 		s.Port = DefaultPort
 	}
 }
+
+// SetDefaults sets default values for the attach configuration
+func (a *AttachConfig) SetDefaults() {
+	if a.Directory == "" {
+		a.Directory = DefaultAttachDir
+	}
+}
+
+// SetDefaults sets default values for the cal configuration
+func (c *CalConfig) SetDefaults() {
+	if c.WeekStart == "" {
+		c.WeekStart = WeekStartSunday
+	}
+}
+
+// GetWeekStart returns the first day of the week (default: Sunday)
+func (c *CalConfig) GetWeekStart() time.Weekday {
+	if c.WeekStart == WeekStartMonday {
+		return time.Monday
+	}
+	return time.Sunday
+}
+
+// SetDefaults sets default values for the import configuration
+func (i *ImportConfig) SetDefaults() {
+	if len(i.DateSources) == 0 {
+		i.DateSources = slices.Clone(DefaultDateSources)
+	}
+	if i.Conflict == "" {
+		i.Conflict = ConflictSkip
+	}
+}
+
+// SetDefaults sets default values for the git configuration
+func (g *GitConfig) SetDefaults() {
+	if g.CommitMessage == "" {
+		g.CommitMessage = DefaultCommitMsg
+	}
+	if g.SyncMessage == "" {
+		g.SyncMessage = DefaultSyncMsg
+	}
+	if g.Remote == "" {
+		g.Remote = DefaultRemote
+	}
+}
+
+// SetDefaults sets default values for the encryption configuration
+func (e *EncryptionConfig) SetDefaults() {
+	if e.PassphraseEnv == "" {
+		e.PassphraseEnv = DefaultPassphrase
+	}
+	if expanded, err := homedir.Expand(e.IdentityFile); err == nil {
+		e.IdentityFile = expanded
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
 )
 
@@ -24,6 +25,30 @@ func TestConfig_Validate(t *testing.T) {
 			config:  Config{},
 			wantErr: true,
 		},
+		{
+			name: "attachments in a parent directory inside base_directory",
+			config: Config{
+				Common: CommonConfig{BaseDirectory: "/home/user/journal", PathFormat: "2006/2006-01-02.md"},
+				Attach: AttachConfig{Directory: "../attachments/{{ .Name }}"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "attachments outside base_directory",
+			config: Config{
+				Common: CommonConfig{BaseDirectory: "/home/user/journal", PathFormat: "2006/2006-01-02.md"},
+				Attach: AttachConfig{Directory: "../../x/{{ .Name }}"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid attachments directory template",
+			config: Config{
+				Common: CommonConfig{BaseDirectory: "/home/user/journal"},
+				Attach: AttachConfig{Directory: "{{ .Name"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -85,6 +110,21 @@ func TestBuildConfig_Validate(t *testing.T) {
 			config:  BuildConfig{Sort: "invalid"},
 			wantErr: true,
 		},
+		{
+			name:    "negative jobs",
+			config:  BuildConfig{Jobs: -1},
+			wantErr: true,
+		},
+		{
+			name:    "theme",
+			config:  BuildConfig{Theme: "portal"},
+			wantErr: false,
+		},
+		{
+			name:    "theme path",
+			config:  BuildConfig{Theme: "../portal"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -97,6 +137,40 @@ func TestBuildConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestBuildConfig_ResolvePaths(t *testing.T) {
+	tests := []struct {
+		name        string
+		templateDir string
+		want        string
+	}{
+		{name: "unset", templateDir: "", want: ""},
+		{name: "relative", templateDir: "templates", want: filepath.Join("/etc/jnal", "templates")},
+		{name: "absolute", templateDir: "/srv/templates", want: "/srv/templates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := BuildConfig{TemplateDir: tt.templateDir}
+			b.ResolvePaths("/etc/jnal")
+			if b.TemplateDir != tt.want {
+				t.Errorf("TemplateDir = %q, want %q", b.TemplateDir, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_ThemeDir(t *testing.T) {
+	cfg := Config{Dir: "/etc/jnal"}
+	if dir, err := cfg.ThemeDir(); err != nil || dir != "" {
+		t.Errorf("ThemeDir() without theme = %q, %v, want empty", dir, err)
+	}
+
+	cfg.Build.Theme = "portal"
+	if dir, err := cfg.ThemeDir(); err != nil || dir != filepath.Join("/etc/jnal", "themes", "portal") {
+		t.Errorf("ThemeDir() = %q, %v", dir, err)
+	}
+}
+
 func TestServeConfig_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -149,7 +223,127 @@ func TestConfig_SetDefaults(t *testing.T) {
 	if cfg.Build.Sort != DefaultSort {
 		t.Errorf("Build.Sort = %v, want %v", cfg.Build.Sort, DefaultSort)
 	}
+	if cfg.Build.TOCMinHeadings != DefaultTOCMinHeadings {
+		t.Errorf("Build.TOCMinHeadings = %v, want %v", cfg.Build.TOCMinHeadings, DefaultTOCMinHeadings)
+	}
 	if cfg.Serve.Port != DefaultPort {
 		t.Errorf("Serve.Port = %v, want %v", cfg.Serve.Port, DefaultPort)
 	}
+	if cfg.Attach.Directory != DefaultAttachDir {
+		t.Errorf("Attach.Directory = %v, want %v", cfg.Attach.Directory, DefaultAttachDir)
+	}
+}
+
+func TestCalConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CalConfig
+		wantErr bool
+	}{
+		{
+			name:    "monday",
+			config:  CalConfig{WeekStart: "monday"},
+			wantErr: false,
+		},
+		{
+			name:    "empty values are valid",
+			config:  CalConfig{},
+			wantErr: false,
+		},
+		{
+			name:    "invalid week_start",
+			config:  CalConfig{WeekStart: "friday"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CalConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ImportConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			config:  ImportConfig{DateSources: []string{"heading", "filename"}, Conflict: "rename"},
+			wantErr: false,
+		},
+		{
+			name:    "empty values are valid",
+			config:  ImportConfig{},
+			wantErr: false,
+		},
+		{
+			name:    "invalid date source",
+			config:  ImportConfig{DateSources: []string{"exif"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate date source",
+			config:  ImportConfig{DateSources: []string{"mtime", "mtime"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid conflict",
+			config:  ImportConfig{Conflict: "overwrite"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ImportConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGitConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  GitConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			config:  GitConfig{AutoCommit: true, CommitMessage: "journal: {{ .Date }}", Remote: "backup"},
+			wantErr: false,
+		},
+		{
+			name:    "empty values are valid",
+			config:  GitConfig{},
+			wantErr: false,
+		},
+		{
+			name:    "invalid commit message template",
+			config:  GitConfig{CommitMessage: "entry: {{ .Date"},
+			wantErr: true,
+		},
+		{
+			name:    "remote looks like an option",
+			config:  GitConfig{Remote: "--upload-pack=evil"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GitConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
 }
@@ -53,7 +53,12 @@ func Load(configFile string) (*Config, error) {
 		return nil, fmt.Errorf("unmarshaling config: %w", err)
 	}
 
+	if dir, err := filepath.Abs(filepath.Dir(v.ConfigFileUsed())); err == nil {
+		cfg.Dir = dir
+	}
+
 	cfg.SetDefaults()
+	cfg.Build.ResolvePaths(cfg.Dir)
 
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("validating config: %w", err)
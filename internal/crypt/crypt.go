@@ -0,0 +1,185 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// Ext is the file extension appended to encrypted files
+const Ext = ".age"
+
+// ageHeader starts every binary age file
+const ageHeader = "age-encryption.org/v1\n"
+
+// ErrPassphraseRequired is returned when a passphrase-protected identity file is loaded without a passphrase
+var ErrPassphraseRequired = errors.New("passphrase required")
+
+// Keys encrypts to and decrypts with a set of age X25519 identities
+type Keys struct {
+	identities []age.Identity
+	recipients []age.Recipient
+}
+
+// GenerateIdentityFile returns the content of a new identity file and its public key.
+// The file is protected with the passphrase unless it is empty.
+func GenerateIdentityFile(passphrase string) ([]byte, string, error) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, "", fmt.Errorf("generating identity: %w", err)
+	}
+	publicKey := identity.Recipient().String()
+	data := []byte(fmt.Sprintf("# public key: %s\n%s\n", publicKey, identity))
+
+	if passphrase == "" {
+		return data, publicKey, nil
+	}
+
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, "", fmt.Errorf("protecting identity: %w", err)
+	}
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+	if err := encryptTo(aw, data, recipient); err != nil {
+		return nil, "", fmt.Errorf("protecting identity: %w", err)
+	}
+	if err := aw.Close(); err != nil {
+		return nil, "", fmt.Errorf("protecting identity: %w", err)
+	}
+	return buf.Bytes(), publicKey, nil
+}
+
+// ParseIdentityFile parses an age identity file such as one written by age-keygen.
+// A passphrase-protected file is decrypted with the passphrase returned by passphrase,
+// which is only called for protected files.
+func ParseIdentityFile(data []byte, passphrase func() (string, error)) (*Keys, error) {
+	if IsEncrypted(data) {
+		if passphrase == nil {
+			return nil, ErrPassphraseRequired
+		}
+		pass, err := passphrase()
+		if err != nil {
+			return nil, err
+		}
+		if pass == "" {
+			return nil, ErrPassphraseRequired
+		}
+		identity, err := age.NewScryptIdentity(pass)
+		if err != nil {
+			return nil, err
+		}
+		data, err = decryptWith(data, identity)
+		if err != nil {
+			return nil, fmt.Errorf("decrypting identity file (wrong passphrase?): %w", err)
+		}
+	}
+
+	identities, err := age.ParseIdentities(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing identity file: %w", err)
+	}
+
+	keys := &Keys{identities: identities}
+	for _, identity := range identities {
+		x, ok := identity.(*age.X25519Identity)
+		if !ok {
+			return nil, fmt.Errorf("unsupported identity type %T", identity)
+		}
+		keys.recipients = append(keys.recipients, x.Recipient())
+	}
+	return keys, nil
+}
+
+// Encrypt encrypts plaintext to all identities of the keys
+func (k *Keys) Encrypt(plaintext []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encryptTo(&buf, plaintext, k.recipients...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decrypt decrypts data encrypted to one of the identities of the keys
+func (k *Keys) Decrypt(data []byte) ([]byte, error) {
+	return decryptWith(data, k.identities...)
+}
+
+// IsEncrypted reports whether data is an age file, binary or armored
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(ageHeader)) ||
+		bytes.HasPrefix(bytes.TrimSpace(data), []byte(armor.Header))
+}
+
+// SecureRemove overwrites the file with zeros before removing it so that the plaintext
+// doesn't linger in the freed blocks. Copy-on-write file systems and SSDs may still
+// keep old copies, so plaintext should be kept on memory-backed storage where possible.
+func SecureRemove(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	info, err := f.Stat()
+	if err == nil {
+		_, err = io.CopyN(f, zeroReader{}, info.Size())
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if removeErr := os.Remove(path); removeErr != nil {
+		return removeErr
+	}
+	return err
+}
+
+// encryptTo encrypts plaintext to the recipients and writes it to w
+func encryptTo(w io.Writer, plaintext []byte, recipients ...age.Recipient) error {
+	ew, err := age.Encrypt(w, recipients...)
+	if err != nil {
+		return fmt.Errorf("encrypting: %w", err)
+	}
+	if _, err := ew.Write(plaintext); err != nil {
+		return fmt.Errorf("encrypting: %w", err)
+	}
+	if err := ew.Close(); err != nil {
+		return fmt.Errorf("encrypting: %w", err)
+	}
+	return nil
+}
+
+// decryptWith decrypts binary or armored age data with the identities
+func decryptWith(data []byte, identities ...age.Identity) ([]byte, error) {
+	var src io.Reader = bytes.NewReader(data)
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte(armor.Header)) {
+		src = armor.NewReader(bytes.NewReader(trimmed))
+	}
+
+	r, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting: %w", err)
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting: %w", err)
+	}
+	return plaintext, nil
+}
+
+// zeroReader reads an endless stream of zeros
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
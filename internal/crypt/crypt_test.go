@@ -0,0 +1,87 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeys_RoundTrip(t *testing.T) {
+	data, publicKey, err := GenerateIdentityFile("")
+	if err != nil {
+		t.Fatalf("GenerateIdentityFile() error = %v", err)
+	}
+	if !bytes.Contains(data, []byte(publicKey)) {
+		t.Errorf("identity file doesn't mention the public key %s", publicKey)
+	}
+
+	keys, err := ParseIdentityFile(data, nil)
+	if err != nil {
+		t.Fatalf("ParseIdentityFile() error = %v", err)
+	}
+
+	ciphertext, err := keys.Encrypt([]byte("secret entry"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if bytes.Contains(ciphertext, []byte("secret")) || !IsEncrypted(ciphertext) {
+		t.Errorf("ciphertext = %q", ciphertext)
+	}
+
+	plaintext, err := keys.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(plaintext) != "secret entry" {
+		t.Errorf("Decrypt() = %q", plaintext)
+	}
+}
+
+func TestParseIdentityFile_Passphrase(t *testing.T) {
+	data, _, err := GenerateIdentityFile("correct horse")
+	if err != nil {
+		t.Fatalf("GenerateIdentityFile() error = %v", err)
+	}
+	if !IsEncrypted(data) {
+		t.Fatal("protected identity file is not encrypted")
+	}
+
+	tests := []struct {
+		name       string
+		passphrase func() (string, error)
+		wantErr    bool
+	}{
+		{name: "correct", passphrase: func() (string, error) { return "correct horse", nil }},
+		{name: "wrong", passphrase: func() (string, error) { return "wrong", nil }, wantErr: true},
+		{name: "missing", passphrase: nil, wantErr: true},
+		{name: "prompt fails", passphrase: func() (string, error) { return "", errors.New("no tty") }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseIdentityFile(data, tt.passphrase)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseIdentityFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecureRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plain.md")
+	if err := os.WriteFile(path, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SecureRemove(path); err != nil {
+		t.Fatalf("SecureRemove() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file still exists: %v", err)
+	}
+	if err := SecureRemove(path); err != nil {
+		t.Errorf("SecureRemove() of a missing file error = %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+// Package epub writes EPUB 3 publications.
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Paths inside the container
+const (
+	mimetype      = "application/epub+zip"
+	contentDir    = "OEBPS"
+	packagePath   = contentDir + "/content.opf"
+	navFile       = "nav.xhtml"
+	styleFile     = "style.css"
+	chapterSuffix = ".xhtml"
+)
+
+// Book represents an EPUB 3 publication
+type Book struct {
+	Title string
+	// Language is a BCP 47 language tag (default: en)
+	Language string
+	// Identifier uniquely identifies the publication (e.g. urn:uuid:...)
+	Identifier string
+	Modified   time.Time
+	CSS        string
+	Chapters   []Chapter
+	// Nav is the table of contents. Items without Href group their children.
+	Nav       []NavItem
+	Resources []Resource
+}
+
+// Chapter represents a content document
+type Chapter struct {
+	// Name is the file name of the chapter without extension
+	Name  string
+	Title string
+	// Body is an XHTML fragment placed in the document's body
+	Body string
+}
+
+// Href returns the link to the chapter from other content documents
+func (c Chapter) Href() string {
+	return url.PathEscape(c.Name) + chapterSuffix
+}
+
+// NavItem represents an item of the table of contents
+type NavItem struct {
+	Title    string
+	Href     string
+	Children []NavItem
+}
+
+// Resource represents a file referenced by chapters, such as an image
+type Resource struct {
+	// Path is the slash-separated path relative to the chapters
+	Path string
+	Open func() (io.ReadCloser, error)
+}
+
+// MediaType returns the media type of a resource supported by EPUB reading systems
+func MediaType(name string) (string, bool) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".png":
+		return "image/png", true
+	case ".jpg", ".jpeg":
+		return "image/jpeg", true
+	case ".gif":
+		return "image/gif", true
+	case ".svg":
+		return "image/svg+xml", true
+	case ".webp":
+		return "image/webp", true
+	}
+	return "", false
+}
+
+// Write writes the book as an EPUB container
+func (b *Book) Write(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	// The mimetype must come first, uncompressed and without extra fields
+	if err := writeStored(zw, "mimetype", []byte(mimetype)); err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		tmpl *template.Template
+		data any
+	}{
+		{"META-INF/container.xml", containerTemplate, packagePath},
+		{packagePath, packageTemplate, b},
+		{contentDir + "/" + navFile, navTemplate, b},
+	}
+	for _, f := range files {
+		if err := writeTemplate(zw, f.name, f.tmpl, f.data); err != nil {
+			return err
+		}
+	}
+
+	if err := writeFile(zw, contentDir+"/"+styleFile, []byte(b.CSS)); err != nil {
+		return err
+	}
+
+	for _, c := range b.Chapters {
+		data := struct {
+			*Book
+			Chapter Chapter
+		}{b, c}
+		if err := writeTemplate(zw, contentDir+"/"+c.Name+chapterSuffix, chapterTemplate, data); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range b.Resources {
+		if err := writeResource(zw, r); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// lang returns the language of the book
+func (b *Book) lang() string {
+	if b.Language == "" {
+		return "en"
+	}
+	return b.Language
+}
+
+// writeStored writes an uncompressed file without a data descriptor
+func writeStored(zw *zip.Writer, name string, data []byte) error {
+	fw, err := zw.CreateRaw(&zip.FileHeader{
+		Name:               name,
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(data),
+		CompressedSize64:   uint64(len(data)),
+		UncompressedSize64: uint64(len(data)),
+	})
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	_, err = fw.Write(data)
+	return err
+}
+
+// writeFile writes a compressed file
+func writeFile(zw *zip.Writer, name string, data []byte) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	_, err = fw.Write(data)
+	return err
+}
+
+// writeTemplate writes the result of executing the template as a compressed file
+func writeTemplate(zw *zip.Writer, name string, tmpl *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("generating %s: %w", name, err)
+	}
+	return writeFile(zw, name, buf.Bytes())
+}
+
+// writeResource copies a resource into the container
+func writeResource(zw *zip.Writer, r Resource) error {
+	src, err := r.Open()
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", r.Path, err)
+	}
+	defer src.Close()
+
+	fw, err := zw.Create(contentDir + "/" + r.Path)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", r.Path, err)
+	}
+	if _, err := io.Copy(fw, src); err != nil {
+		return fmt.Errorf("writing %s: %w", r.Path, err)
+	}
+	return nil
+}
+
+// escapePath escapes each segment of a slash-separated path for use in a URL
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// templateFuncs are the functions available in the container templates
+var templateFuncs = template.FuncMap{
+	"xml":       html.EscapeString,
+	"path":      escapePath,
+	"mediaType": func(name string) string { t, _ := MediaType(name); return t },
+	"lang":      (*Book).lang,
+	"modified":  func(t time.Time) string { return t.UTC().Format("2006-01-02T15:04:05Z") },
+}
+
+var containerTemplate = template.Must(template.New("container").Funcs(templateFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="{{ . }}" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`))
+
+var packageTemplate = template.Must(template.New("package").Funcs(templateFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid" xml:lang="{{ lang . | xml }}">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="uid">{{ xml .Identifier }}</dc:identifier>
+    <dc:title>{{ xml .Title }}</dc:title>
+    <dc:language>{{ lang . | xml }}</dc:language>
+    <meta property="dcterms:modified">{{ modified .Modified }}</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="` + navFile + `" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="css" href="` + styleFile + `" media-type="text/css"/>
+    {{- range $i, $c := .Chapters }}
+    <item id="ch{{ $i }}" href="{{ $c.Href | xml }}" media-type="application/xhtml+xml"/>
+    {{- end }}
+    {{- range $i, $r := .Resources }}
+    <item id="res{{ $i }}" href="{{ path $r.Path | xml }}" media-type="{{ mediaType $r.Path }}"/>
+    {{- end }}
+  </manifest>
+  <spine>
+    <itemref idref="nav"/>
+    {{- range $i, $c := .Chapters }}
+    <itemref idref="ch{{ $i }}"/>
+    {{- end }}
+  </spine>
+</package>
+`))
+
+var navTemplate = template.Must(template.New("nav").Funcs(templateFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="{{ lang . | xml }}" xml:lang="{{ lang . | xml }}">
+<head>
+  <meta charset="UTF-8"/>
+  <title>{{ xml .Title }}</title>
+  <link rel="stylesheet" type="text/css" href="` + styleFile + `"/>
+</head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <h1>{{ xml .Title }}</h1>
+    {{- template "items" .Nav }}
+  </nav>
+</body>
+</html>
+{{- define "items" }}
+    <ol>
+    {{- range . }}
+      <li>{{ if .Href }}<a href="{{ xml .Href }}">{{ xml .Title }}</a>{{ else }}<span>{{ xml .Title }}</span>{{ end }}
+      {{- if .Children }}{{ template "items" .Children }}{{ end }}</li>
+    {{- end }}
+    </ol>
+{{- end }}
+`))
+
+var chapterTemplate = template.Must(template.New("chapter").Funcs(templateFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="{{ lang .Book | xml }}" xml:lang="{{ lang .Book | xml }}">
+<head>
+  <meta charset="UTF-8"/>
+  <title>{{ xml .Chapter.Title }}</title>
+  <link rel="stylesheet" type="text/css" href="` + styleFile + `"/>
+</head>
+<body>
+{{ .Chapter.Body }}
+</body>
+</html>
+`))
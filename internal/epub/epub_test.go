@@ -0,0 +1,126 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBook_Write(t *testing.T) {
+	book := &Book{
+		Title:      "Journal & Notes",
+		Identifier: "urn:uuid:0",
+		Modified:   time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC),
+		CSS:        "body { margin: 0; }",
+		Chapters: []Chapter{
+			{Name: "2024-01", Title: "January 2024", Body: `<h1>January 2024</h1><p><img src="img/my%20photo.png" alt="" /></p>`},
+		},
+		Nav: []NavItem{
+			{Title: "2024", Children: []NavItem{{Title: "January 2024", Href: "2024-01.xhtml"}}},
+		},
+		Resources: []Resource{
+			{Path: "img/my photo.png", Open: func() (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("png")), nil
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := book.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+
+	first := zr.File[0]
+	if first.Name != "mimetype" || first.Method != zip.Store || len(first.Extra) != 0 {
+		t.Errorf("first file = %s (method %d), want uncompressed mimetype", first.Name, first.Method)
+	}
+	if !bytes.HasPrefix(buf.Bytes()[30:], []byte("mimetypeapplication/epub+zip")) {
+		t.Error("mimetype is not at the start of the container")
+	}
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"META-INF/container.xml", "OEBPS/content.opf", "OEBPS/nav.xhtml", "OEBPS/2024-01.xhtml"} {
+		content, ok := files[name]
+		if !ok {
+			t.Errorf("missing %s", name)
+			continue
+		}
+		if err := wellFormed(content); err != nil {
+			t.Errorf("%s is not well-formed XML: %v", name, err)
+		}
+	}
+
+	opf := files["OEBPS/content.opf"]
+	for _, want := range []string{
+		"<dc:title>Journal &amp; Notes</dc:title>",
+		`<meta property="dcterms:modified">2024-12-31T12:00:00Z</meta>`,
+		`href="img/my%20photo.png" media-type="image/png"`,
+		`<itemref idref="ch0"/>`,
+	} {
+		if !strings.Contains(opf, want) {
+			t.Errorf("content.opf missing %q", want)
+		}
+	}
+	if !strings.Contains(files["OEBPS/nav.xhtml"], `<span>2024</span>`) || !strings.Contains(files["OEBPS/nav.xhtml"], `<a href="2024-01.xhtml">January 2024</a>`) {
+		t.Errorf("nav.xhtml = %s", files["OEBPS/nav.xhtml"])
+	}
+	if files["OEBPS/img/my photo.png"] != "png" {
+		t.Error("resource was not embedded")
+	}
+}
+
+func TestMediaType(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"a.PNG", "image/png", true},
+		{"a.jpeg", "image/jpeg", true},
+		{"a.svg", "image/svg+xml", true},
+		{"a.pdf", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := MediaType(tt.name)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("MediaType(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// wellFormed reports whether s parses as XML
+func wellFormed(s string) error {
+	d := xml.NewDecoder(strings.NewReader(s))
+	d.Strict = true
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrNotRepository is returned when a directory is not inside a git work tree
+var ErrNotRepository = errors.New("not a git repository")
+
+// mergeAttributes makes git merge concurrent changes to Markdown files by keeping the
+// lines of both sides instead of stopping with a conflict
+const mergeAttributes = "*.md merge=union\n*.markdown merge=union\n"
+
+// Repo runs git commands in a directory of a work tree
+type Repo struct {
+	dir string
+}
+
+// SyncOptions represents options for synchronizing with a remote
+type SyncOptions struct {
+	// Remote is the remote to pull from and push to
+	Remote string
+	// Branch is the remote branch (default: the current branch)
+	Branch string
+	// Message is the commit message for local changes
+	Message string
+}
+
+// SyncResult describes what a sync did
+type SyncResult struct {
+	// Committed is true if local changes were committed
+	Committed bool
+	// Pulled is the number of commits pulled from the remote
+	Pulled int
+	// Pushed is the number of commits pushed to the remote
+	Pushed int
+	// Merged lists the Markdown files changed both locally and remotely, whose lines were combined
+	Merged []string
+}
+
+// ConflictError is returned when a sync stops at changes that can't be merged automatically
+type ConflictError struct {
+	Files []string
+}
+
+func (e *ConflictError) Error() string {
+	return "conflicting changes in " + strings.Join(e.Files, ", ")
+}
+
+// Open returns the repository containing dir
+func Open(dir string) (*Repo, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is not installed: %w", err)
+	}
+	r := &Repo{dir: dir}
+	if out, err := r.run("rev-parse", "--is-inside-work-tree"); err != nil || out != "true" {
+		return nil, fmt.Errorf("%s: %w", dir, ErrNotRepository)
+	}
+	return r, nil
+}
+
+// Tracked reports whether git tracks the file, or any file in the directory
+func (r *Repo) Tracked(path string) bool {
+	out, err := r.run("ls-files", "--", r.rel(path))
+	return err == nil && out != ""
+}
+
+// Move moves a tracked file or directory with git mv
+func (r *Repo) Move(from, to string) error {
+	_, err := r.run("mv", "--", r.rel(from), r.rel(to))
+	return err
+}
+
+// Commit stages all changes to the paths and commits them.
+// It reports false without committing if the paths have no changes.
+func (r *Repo) Commit(message string, paths ...string) (bool, error) {
+	pathspecs := make([]string, len(paths))
+	for i, p := range paths {
+		pathspecs[i] = r.rel(p)
+	}
+
+	if _, err := r.run(append([]string{"add", "--all", "--"}, pathspecs...)...); err != nil {
+		return false, err
+	}
+	// diff --quiet exits with 1 if there are changes
+	if _, err := r.run(append([]string{"diff", "--cached", "--quiet", "--"}, pathspecs...)...); err == nil {
+		return false, nil
+	}
+	if _, err := r.run(append([]string{"commit", "--quiet", "--message", message, "--"}, pathspecs...)...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Sync commits all changes in the repository directory, rebases them onto the remote
+// branch and pushes the result. Concurrent changes to Markdown files are combined line
+// by line; if other files conflict, the rebase is aborted and a *ConflictError is
+// returned with the local commits left in place.
+func (r *Repo) Sync(opts SyncOptions) (*SyncResult, error) {
+	branch := opts.Branch
+	if branch == "" {
+		current, err := r.run("symbolic-ref", "--quiet", "--short", "HEAD")
+		if err != nil {
+			return nil, fmt.Errorf("detecting current branch: %w", err)
+		}
+		branch = current
+	}
+	if _, err := r.run("remote", "get-url", opts.Remote); err != nil {
+		return nil, fmt.Errorf("remote %q not found: %w", opts.Remote, err)
+	}
+
+	result := &SyncResult{}
+
+	committed, err := r.Commit(opts.Message, r.dir)
+	if err != nil {
+		return nil, fmt.Errorf("committing local changes: %w", err)
+	}
+	result.Committed = committed
+
+	if _, err := r.run("fetch", "--quiet", opts.Remote); err != nil {
+		return result, fmt.Errorf("fetching %s: %w", opts.Remote, err)
+	}
+
+	upstream := "refs/remotes/" + opts.Remote + "/" + branch
+	if _, err := r.run("rev-parse", "--verify", "--quiet", upstream); err == nil {
+		if err := r.rebase(upstream, result); err != nil {
+			return result, err
+		}
+	} else {
+		// The remote branch doesn't exist yet; push creates it
+		upstream = ""
+	}
+
+	pushed, err := r.count(upstream, "HEAD")
+	if err != nil {
+		return result, err
+	}
+	if pushed > 0 {
+		if _, err := r.run("push", "--quiet", opts.Remote, "HEAD:refs/heads/"+branch); err != nil {
+			return result, fmt.Errorf("pushing to %s: %w", opts.Remote, err)
+		}
+	}
+	result.Pushed = pushed
+
+	return result, nil
+}
+
+// rebase rebases the local commits onto upstream
+func (r *Repo) rebase(upstream string, result *SyncResult) error {
+	pulled, err := r.count("HEAD", upstream)
+	if err != nil {
+		return err
+	}
+	if pulled == 0 {
+		return nil
+	}
+	result.Pulled = pulled
+
+	base, err := r.run("merge-base", "HEAD", upstream)
+	if err != nil {
+		return fmt.Errorf("finding merge base: %w", err)
+	}
+	local, err := r.changedFiles(base, "HEAD")
+	if err != nil {
+		return err
+	}
+	remote, err := r.changedFiles(base, upstream)
+	if err != nil {
+		return err
+	}
+	for _, f := range local {
+		if isMarkdown(f) && slices.Contains(remote, f) {
+			result.Merged = append(result.Merged, f)
+		}
+	}
+
+	attributes, err := os.CreateTemp("", "jnal-gitattributes-*")
+	if err != nil {
+		return fmt.Errorf("creating attributes file: %w", err)
+	}
+	defer os.Remove(attributes.Name())
+	if _, err := attributes.WriteString(mergeAttributes); err != nil {
+		attributes.Close()
+		return fmt.Errorf("writing attributes file: %w", err)
+	}
+	if err := attributes.Close(); err != nil {
+		return fmt.Errorf("writing attributes file: %w", err)
+	}
+
+	if _, err := r.run("-c", "core.attributesFile="+attributes.Name(), "rebase", "--quiet", "--autostash", upstream); err != nil {
+		conflicts, _ := r.run("diff", "--name-only", "--diff-filter=U")
+		if _, abortErr := r.run("rebase", "--abort"); abortErr != nil {
+			return fmt.Errorf("rebasing onto %s: %w (aborting failed: %v)", upstream, err, abortErr)
+		}
+		if conflicts != "" {
+			return &ConflictError{Files: strings.Split(conflicts, "\n")}
+		}
+		return fmt.Errorf("rebasing onto %s: %w", upstream, err)
+	}
+	return nil
+}
+
+// count returns the number of commits reachable from to but not from from.
+// An empty from counts all commits reachable from to.
+func (r *Repo) count(from, to string) (int, error) {
+	rng := to
+	if from != "" {
+		rng = from + ".." + to
+	}
+	out, err := r.run("rev-list", "--count", rng)
+	if err != nil {
+		return 0, fmt.Errorf("counting commits: %w", err)
+	}
+	return strconv.Atoi(out)
+}
+
+// changedFiles returns the files changed between two commits
+func (r *Repo) changedFiles(from, to string) ([]string, error) {
+	out, err := r.run("diff", "--name-only", "--relative", from, to)
+	if err != nil {
+		return nil, fmt.Errorf("listing changed files: %w", err)
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// rel returns the path relative to the repository directory, where git commands are run.
+// Relative paths keep working when the directory is reached through a symlink.
+func (r *Repo) rel(path string) string {
+	if rel, err := filepath.Rel(r.dir, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// run runs a git command in the repository directory and returns its trimmed output
+func (r *Repo) run(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		name := args[0]
+		if name == "-c" && len(args) > 2 {
+			name = args[2]
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %w: %s", name, err, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// isMarkdown reports whether the file is merged line by line
+func isMarkdown(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
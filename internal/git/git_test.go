@@ -0,0 +1,190 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupGit isolates git from the user's configuration and sets a commit identity
+func setupGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newClones creates a bare repository with an initial commit and two clones of it
+func newClones(t *testing.T, files map[string]string) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	bare := filepath.Join(root, "remote.git")
+	gitCmd(t, root, "init", "--quiet", "--bare", "--initial-branch=main", bare)
+
+	a := filepath.Join(root, "a")
+	gitCmd(t, root, "clone", "--quiet", bare, a)
+	gitCmd(t, a, "checkout", "--quiet", "-b", "main")
+	for name, content := range files {
+		writeFile(t, filepath.Join(a, name), content)
+	}
+	gitCmd(t, a, "add", "--all")
+	gitCmd(t, a, "commit", "--quiet", "-m", "init")
+	gitCmd(t, a, "push", "--quiet", "origin", "main")
+
+	b := filepath.Join(root, "b")
+	gitCmd(t, root, "clone", "--quiet", bare, b)
+	return a, b
+}
+
+func TestOpen_NotRepository(t *testing.T) {
+	setupGit(t)
+	if _, err := Open(t.TempDir()); !errors.Is(err, ErrNotRepository) {
+		t.Errorf("Open() error = %v, want ErrNotRepository", err)
+	}
+}
+
+func TestRepo_Commit(t *testing.T) {
+	setupGit(t)
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "--quiet")
+	writeFile(t, filepath.Join(dir, "2024-01-15.md"), "entry\n")
+	writeFile(t, filepath.Join(dir, "other.txt"), "unrelated\n")
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	committed, err := repo.Commit("entry: 2024-01-15", filepath.Join(dir, "2024-01-15.md"))
+	if err != nil || !committed {
+		t.Fatalf("Commit() = %v, %v, want true", committed, err)
+	}
+	if got := gitCmd(t, dir, "log", "--format=%s"); got != "entry: 2024-01-15" {
+		t.Errorf("log = %q", got)
+	}
+	if got := gitCmd(t, dir, "ls-files"); got != "2024-01-15.md" {
+		t.Errorf("committed files = %q, want only the entry", got)
+	}
+
+	committed, err = repo.Commit("entry: 2024-01-15", filepath.Join(dir, "2024-01-15.md"))
+	if err != nil || committed {
+		t.Errorf("Commit() without changes = %v, %v, want false", committed, err)
+	}
+}
+
+func TestRepo_Sync(t *testing.T) {
+	setupGit(t)
+	a, b := newClones(t, map[string]string{"2024-01-15.md": "# 2024-01-15\n"})
+
+	// Both sides append to the same entry
+	writeFile(t, filepath.Join(a, "2024-01-15.md"), "# 2024-01-15\n\nfrom a\n")
+	writeFile(t, filepath.Join(b, "2024-01-15.md"), "# 2024-01-15\n\nfrom b\n")
+	writeFile(t, filepath.Join(b, "2024-01-16.md"), "new in b\n")
+
+	repoA, err := Open(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := repoA.Sync(SyncOptions{Remote: "origin", Message: "sync a"})
+	if err != nil {
+		t.Fatalf("Sync(a) error = %v", err)
+	}
+	if !result.Committed || result.Pulled != 0 || result.Pushed != 1 {
+		t.Errorf("Sync(a) = %+v", result)
+	}
+
+	repoB, err := Open(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = repoB.Sync(SyncOptions{Remote: "origin", Message: "sync b"})
+	if err != nil {
+		t.Fatalf("Sync(b) error = %v", err)
+	}
+	if !result.Committed || result.Pulled != 1 || result.Pushed != 1 {
+		t.Errorf("Sync(b) = %+v", result)
+	}
+	if len(result.Merged) != 1 || result.Merged[0] != "2024-01-15.md" {
+		t.Errorf("Merged = %v, want [2024-01-15.md]", result.Merged)
+	}
+
+	data, err := os.ReadFile(filepath.Join(b, "2024-01-15.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "from a") || !strings.Contains(string(data), "from b") ||
+		strings.Contains(string(data), "<<<<<<<") {
+		t.Errorf("merged entry = %q, want both lines without markers", data)
+	}
+
+	// a pulls the merged result
+	result, err = repoA.Sync(SyncOptions{Remote: "origin", Message: "sync a"})
+	if err != nil {
+		t.Fatalf("Sync(a) error = %v", err)
+	}
+	if result.Committed || result.Pulled != 1 || result.Pushed != 0 {
+		t.Errorf("Sync(a) = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(a, "2024-01-16.md")); err != nil {
+		t.Errorf("entry from b not pulled: %v", err)
+	}
+}
+
+func TestRepo_Sync_Conflict(t *testing.T) {
+	setupGit(t)
+	a, b := newClones(t, map[string]string{"notes.txt": "base\n"})
+
+	writeFile(t, filepath.Join(a, "notes.txt"), "from a\n")
+	writeFile(t, filepath.Join(b, "notes.txt"), "from b\n")
+
+	repoA, _ := Open(a)
+	if _, err := repoA.Sync(SyncOptions{Remote: "origin", Message: "sync a"}); err != nil {
+		t.Fatalf("Sync(a) error = %v", err)
+	}
+
+	repoB, _ := Open(b)
+	_, err := repoB.Sync(SyncOptions{Remote: "origin", Message: "sync b"})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Sync(b) error = %v, want ConflictError", err)
+	}
+	if len(conflict.Files) != 1 || conflict.Files[0] != "notes.txt" {
+		t.Errorf("conflict files = %v", conflict.Files)
+	}
+
+	// The rebase is aborted and the local commit kept
+	if _, err := os.Stat(filepath.Join(b, ".git", "rebase-merge")); !os.IsNotExist(err) {
+		t.Errorf("rebase still in progress")
+	}
+	if got := gitCmd(t, b, "log", "-1", "--format=%s"); got != "sync b" {
+		t.Errorf("HEAD = %q, want the local commit", got)
+	}
+}
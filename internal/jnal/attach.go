@@ -0,0 +1,311 @@
+package jnal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/longkey1/jnal/internal/config"
+)
+
+// imageExtensions lists file extensions that are referenced as Markdown images
+var imageExtensions = map[string]bool{
+	".apng": true,
+	".avif": true,
+	".bmp":  true,
+	".gif":  true,
+	".jpeg": true,
+	".jpg":  true,
+	".png":  true,
+	".svg":  true,
+	".webp": true,
+}
+
+// AttachOptions represents options for attaching a file to an entry
+type AttachOptions struct {
+	// Name overrides the attachment file name (default: the source file name)
+	Name string
+	// Move removes the source file after it has been attached
+	Move bool
+}
+
+// Attachment represents a file attached to a journal entry
+type Attachment struct {
+	// Path is the absolute path of the attachment
+	Path string
+	// Link is the Markdown reference inserted into the entry
+	Link string
+	// Reused is true if an attachment with the same content already existed
+	Reused bool
+}
+
+// GetAttachmentDir returns the attachments directory for the entry on the given date
+func (j *Journal) GetAttachmentDir(date time.Time) (string, error) {
+	return j.attachmentDirFor(j.GetEntryPath(date), date)
+}
+
+// attachmentDirFor returns the attachments directory for the entry file at entryPath
+func (j *Journal) attachmentDirFor(entryPath string, date time.Time) (string, error) {
+	name := filepath.Base(strings.TrimSuffix(entryPath, EncryptedExt))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+
+	dir, err := j.executeTemplate(j.cfg.Attach.Directory, map[string]interface{}{
+		"Name": name,
+		"Date": date.Format(j.cfg.Common.DateFormat),
+	})
+	if err != nil {
+		return "", fmt.Errorf("building attachment directory: %w", err)
+	}
+
+	dir = filepath.Join(filepath.Dir(entryPath), filepath.FromSlash(dir))
+	if !isWithin(dir, j.GetBaseDir()) {
+		return "", fmt.Errorf("attachment directory %s is outside base_directory", dir)
+	}
+	return dir, nil
+}
+
+// Attach copies (or moves) a file into the entry's attachments directory and
+// appends a Markdown reference to the entry. Files whose content is already
+// attached are reused, and existing attachments are never overwritten.
+func (j *Journal) Attach(date time.Time, src string, opts AttachOptions) (*Attachment, error) {
+	attachment, err := j.storeAttachment(date, src, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't reference a reused attachment twice
+	entryPath := j.GetEntryPath(date)
+	if attachment.Reused {
+		if data, err := j.ReadEntry(entryPath); err == nil && bytes.Contains(data, []byte(attachment.Link)) {
+			return attachment, nil
+		}
+	}
+
+	if err := j.AppendEntry(date, attachment.Link); err != nil {
+		return nil, fmt.Errorf("appending link: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// storeAttachment copies (or moves) a file into the entry's attachments directory
+// without referencing it from the entry
+func (j *Journal) storeAttachment(date time.Time, src string, opts AttachOptions) (*Attachment, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", src, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("not a regular file: %s", src)
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = filepath.Base(src)
+	}
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid attachment name %q", name)
+	}
+
+	dir, err := j.GetAttachmentDir(date)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := hashFile(src)
+	if err != nil {
+		return nil, fmt.Errorf("hashing %s: %w", src, err)
+	}
+
+	attachment := &Attachment{}
+
+	existing, err := findByHash(dir, hash)
+	if err != nil {
+		return nil, fmt.Errorf("searching attachments: %w", err)
+	}
+
+	if existing != "" {
+		attachment.Path = existing
+		attachment.Reused = true
+		if opts.Move {
+			if err := os.Remove(src); err != nil {
+				return nil, fmt.Errorf("removing %s: %w", src, err)
+			}
+		}
+	} else {
+		dst := filepath.Join(dir, name)
+		if _, err := os.Lstat(dst); err == nil {
+			return nil, fmt.Errorf("attachment already exists: %s (use --name to choose another name)", dst)
+		}
+
+		if err := os.MkdirAll(dir, config.DirPermission); err != nil {
+			return nil, fmt.Errorf("creating directory %s: %w", dir, err)
+		}
+
+		if opts.Move {
+			err = moveFile(src, dst)
+		} else {
+			err = copyFile(src, dst)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("attaching %s: %w", src, err)
+		}
+		attachment.Path = dst
+	}
+
+	entryPath := j.GetEntryPath(date)
+	attachment.Link, err = attachmentLink(filepath.Dir(entryPath), attachment.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// AppendEntry appends text as a new paragraph to the entry for the given date,
+// creating the entry first if it doesn't exist
+func (j *Journal) AppendEntry(date time.Time, text string) error {
+	entryPath, err := j.CreateEntry(date)
+	if err != nil {
+		return err
+	}
+
+	data, err := j.ReadEntry(entryPath)
+	if err != nil {
+		return fmt.Errorf("reading entry %s: %w", entryPath, err)
+	}
+
+	var buf bytes.Buffer
+	if len(bytes.TrimSpace(data)) > 0 {
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			buf.WriteString("\n")
+		}
+		if !bytes.HasSuffix(data, []byte("\n\n")) {
+			buf.WriteString("\n")
+		}
+	}
+	buf.WriteString(text)
+	buf.WriteString("\n")
+
+	// Encrypted entries can't be appended to and are rewritten as a whole
+	if IsEncrypted(entryPath) {
+		return j.WriteEntry(entryPath, append(data, buf.Bytes()...))
+	}
+
+	file, err := os.OpenFile(entryPath, os.O_WRONLY|os.O_APPEND, config.FilePermission)
+	if err != nil {
+		return fmt.Errorf("opening entry %s: %w", entryPath, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing entry %s: %w", entryPath, err)
+	}
+
+	return nil
+}
+
+// attachmentLink builds a Markdown link (or image) to the attachment relative to the entry directory
+func attachmentLink(entryDir, attachmentPath string) (string, error) {
+	rel, err := filepath.Rel(entryDir, attachmentPath)
+	if err != nil {
+		return "", fmt.Errorf("resolving attachment path: %w", err)
+	}
+
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	dest := strings.Join(segments, "/")
+
+	name := filepath.Base(attachmentPath)
+	if imageExtensions[strings.ToLower(filepath.Ext(name))] {
+		return fmt.Sprintf("![%s](%s)", name, dest), nil
+	}
+	return fmt.Sprintf("[%s](%s)", name, dest), nil
+}
+
+// findByHash returns the path of a file in dir with the given content hash,
+// or an empty string if there is none
+func findByHash(dir string, hash []byte) (string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	for _, f := range files {
+		if !f.Type().IsRegular() {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		h, err := hashFile(path)
+		if err != nil {
+			return "", err
+		}
+		if bytes.Equal(h, hash) {
+			return path, nil
+		}
+	}
+
+	return "", nil
+}
+
+// hashFile returns the SHA-256 hash of the file content
+func hashFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// copyFile copies src to dst, failing if dst already exists
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, config.FilePermission)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// moveFile moves src to dst, falling back to copy and remove across file systems
+func moveFile(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return os.Remove(src)
+	} else if errors.Is(err, os.ErrExist) {
+		return err
+	}
+
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
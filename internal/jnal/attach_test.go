@@ -0,0 +1,109 @@
+package jnal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/longkey1/jnal/internal/config"
+)
+
+func newTestJournal(t *testing.T, pathFormat string) *Journal {
+	t.Helper()
+	cfg := &config.Config{
+		Common: config.CommonConfig{
+			BaseDirectory: t.TempDir(),
+			PathFormat:    pathFormat,
+		},
+	}
+	cfg.SetDefaults()
+	return NewJournal(cfg)
+}
+
+func TestAttachmentLink(t *testing.T) {
+	tests := []struct {
+		name       string
+		attachment string
+		want       string
+	}{
+		{name: "image", attachment: "/j/attachments/2024-01-15/shot.PNG", want: "![shot.PNG](attachments/2024-01-15/shot.PNG)"},
+		{name: "pdf", attachment: "/j/attachments/2024-01-15/slides.pdf", want: "[slides.pdf](attachments/2024-01-15/slides.pdf)"},
+		{name: "escaped", attachment: "/j/attachments/2024-01-15/my notes.pdf", want: "[my notes.pdf](attachments/2024-01-15/my%20notes.pdf)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := attachmentLink("/j", tt.attachment)
+			if err != nil {
+				t.Fatalf("attachmentLink() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("attachmentLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJournal_Attach(t *testing.T) {
+	j := newTestJournal(t, "2006/2006-01-02.md")
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	src := filepath.Join(t.TempDir(), "shot.png")
+	if err := os.WriteFile(src, []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := j.Attach(date, src, AttachOptions{})
+	if err != nil {
+		t.Fatalf("Attach() error = %v", err)
+	}
+	wantPath := filepath.Join(j.GetBaseDir(), "2024", "attachments", "2024-01-15", "shot.png")
+	if first.Path != wantPath {
+		t.Errorf("Attach() path = %q, want %q", first.Path, wantPath)
+	}
+
+	// Same content under another name is deduplicated
+	second, err := j.Attach(date, src, AttachOptions{Name: "copy.png"})
+	if err != nil {
+		t.Fatalf("Attach() error = %v", err)
+	}
+	if !second.Reused || second.Path != first.Path {
+		t.Errorf("Attach() = %+v, want reuse of %q", second, first.Path)
+	}
+
+	// Different content under an existing name is refused
+	if err := os.WriteFile(src, []byte("other image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Attach(date, src, AttachOptions{}); err == nil {
+		t.Error("Attach() succeeded, want error for existing attachment")
+	}
+
+	data, err := os.ReadFile(j.GetEntryPath(date))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), first.Link); got != 1 {
+		t.Errorf("entry references attachment %d times, want 1:\n%s", got, data)
+	}
+}
+
+func TestJournal_Attach_OutsideBaseDir(t *testing.T) {
+	j := newTestJournal(t, "2006/2006-01-02.md")
+	j.cfg.Attach.Directory = "../../x/{{ .Name }}"
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	src := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(src, []byte("notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := j.Attach(date, src, AttachOptions{}); err == nil {
+		t.Error("Attach() succeeded, want error for attachment directory outside base_directory")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(j.GetBaseDir()), "x")); !os.IsNotExist(err) {
+		t.Errorf("attachment directory created outside base_directory: %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package jnal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/crypt"
+)
+
+// EncryptedExt is appended to the path of encrypted entries (2024-01-15.md.age)
+const EncryptedExt = crypt.Ext
+
+// IsEntryFile reports whether the file name has the extension of a plain or encrypted entry
+func IsEntryFile(name string) bool {
+	return filepath.Ext(name) == ".md" || strings.HasSuffix(name, ".md"+EncryptedExt)
+}
+
+// IsEncrypted reports whether the path is an encrypted entry
+func IsEncrypted(path string) bool {
+	return strings.HasSuffix(path, EncryptedExt)
+}
+
+// SetPassphrasePrompt sets the function asking for the identity file passphrase
+// when it isn't set in the environment
+func (j *Journal) SetPassphrasePrompt(prompt func() (string, error)) {
+	j.passphrasePrompt = prompt
+}
+
+// ReadEntry returns the content of the entry file, decrypting encrypted entries
+func (j *Journal) ReadEntry(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !IsEncrypted(path) {
+		return data, err
+	}
+
+	keys, err := j.loadKeys()
+	if err != nil {
+		return nil, err
+	}
+	return keys.Decrypt(data)
+}
+
+// WriteEntry replaces the content of the entry file, encrypting encrypted entries.
+// The file is replaced atomically so that a failed write never leaves a truncated entry.
+func (j *Journal) WriteEntry(path string, data []byte) error {
+	data, err := j.sealEntry(path, data)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".jnal-*")
+	if err != nil {
+		return fmt.Errorf("writing entry %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing entry %s: %w", path, err)
+	}
+	if err := tmp.Chmod(config.FilePermission); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing entry %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing entry %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing entry %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeNewEntry creates the entry file with the content, encrypting encrypted entries
+// and failing if the file exists
+func (j *Journal) writeNewEntry(path, content string) error {
+	data, err := j.sealEntry(path, []byte(content))
+	if err != nil {
+		return err
+	}
+	return writeNewFileFrom(path, bytes.NewReader(data))
+}
+
+// sealEntry encrypts data if the path is an encrypted entry
+func (j *Journal) sealEntry(path string, data []byte) ([]byte, error) {
+	if !IsEncrypted(path) {
+		return data, nil
+	}
+	keys, err := j.loadKeys()
+	if err != nil {
+		return nil, err
+	}
+	return keys.Encrypt(data)
+}
+
+// resolveEntryPath returns the existing plain or encrypted file for the plain entry
+// path, or the path a new entry is written to. Plain entries stay readable after
+// encryption is enabled, and encrypted ones after it is disabled.
+func (j *Journal) resolveEntryPath(path string) string {
+	encrypted := path + EncryptedExt
+	if j.cfg.Encryption.Enabled {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		return encrypted
+	}
+	if _, err := os.Stat(path); err != nil {
+		if _, err := os.Stat(encrypted); err == nil {
+			return encrypted
+		}
+	}
+	return path
+}
+
+// loadKeys loads the identity file once, asking for its passphrase if needed
+func (j *Journal) loadKeys() (*crypt.Keys, error) {
+	j.keysOnce.Do(func() {
+		path := j.cfg.Encryption.IdentityFile
+		if path == "" {
+			j.keysErr = fmt.Errorf("encrypted entries require identity_file in the [encryption] config")
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			j.keysErr = fmt.Errorf("reading identity file: %w", err)
+			return
+		}
+		j.keys, j.keysErr = crypt.ParseIdentityFile(data, j.readPassphrase)
+	})
+	return j.keys, j.keysErr
+}
+
+// readPassphrase returns the identity file passphrase from the environment or the prompt
+func (j *Journal) readPassphrase() (string, error) {
+	if pass := os.Getenv(j.cfg.Encryption.PassphraseEnv); pass != "" {
+		return pass, nil
+	}
+	if j.passphrasePrompt == nil {
+		return "", fmt.Errorf("%w: set $%s", crypt.ErrPassphraseRequired, j.cfg.Encryption.PassphraseEnv)
+	}
+	pass, err := j.passphrasePrompt()
+	if err != nil {
+		return "", fmt.Errorf("reading passphrase: %w (set $%s to provide it)", err, j.cfg.Encryption.PassphraseEnv)
+	}
+	return pass, nil
+}
+
+// EncryptEntries encrypts all plain entries to .md.age files and securely removes
+// the plain files. It returns the paths of the new encrypted entries.
+func (j *Journal) EncryptEntries() ([]string, error) {
+	entries, err := j.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var encrypted []string
+	for _, e := range entries {
+		if IsEncrypted(e.Path) {
+			continue
+		}
+		data, err := os.ReadFile(e.Path)
+		if err != nil {
+			return encrypted, fmt.Errorf("reading entry %s: %w", e.Path, err)
+		}
+		target := e.Path + EncryptedExt
+		if err := j.writeNewEntry(target, string(data)); err != nil {
+			return encrypted, err
+		}
+		if err := crypt.SecureRemove(e.Path); err != nil {
+			return encrypted, fmt.Errorf("removing %s: %w", e.Path, err)
+		}
+		encrypted = append(encrypted, target)
+	}
+	return encrypted, nil
+}
+
+// DecryptEntries decrypts all encrypted entries back to plain Markdown files.
+// It returns the paths of the new plain entries.
+func (j *Journal) DecryptEntries() ([]string, error) {
+	entries, err := j.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var decrypted []string
+	for _, e := range entries {
+		if !IsEncrypted(e.Path) {
+			continue
+		}
+		data, err := j.ReadEntry(e.Path)
+		if err != nil {
+			return decrypted, err
+		}
+		target := strings.TrimSuffix(e.Path, EncryptedExt)
+		if err := writeNewFile(target, string(data)); err != nil {
+			return decrypted, err
+		}
+		if err := os.Remove(e.Path); err != nil {
+			return decrypted, fmt.Errorf("removing %s: %w", e.Path, err)
+		}
+		decrypted = append(decrypted, target)
+	}
+	return decrypted, nil
+}
+
+// EditEntry opens the entry for the date with edit, creating the entry first if needed,
+// and reports whether it was changed. Encrypted entries are edited through a decrypted
+// copy in a private temporary directory, which is re-encrypted and securely removed
+// when edit returns.
+func (j *Journal) EditEntry(date time.Time, edit func(path string) error) (bool, error) {
+	entryPath, err := j.CreateEntry(date)
+	if err != nil {
+		return false, err
+	}
+
+	if !IsEncrypted(entryPath) {
+		before, err := os.ReadFile(entryPath)
+		if err != nil {
+			return false, fmt.Errorf("reading entry %s: %w", entryPath, err)
+		}
+		if err := edit(entryPath); err != nil {
+			return false, err
+		}
+		after, err := os.ReadFile(entryPath)
+		if err != nil {
+			return false, fmt.Errorf("reading entry %s: %w", entryPath, err)
+		}
+		return !bytes.Equal(before, after), nil
+	}
+
+	plaintext, err := j.ReadEntry(entryPath)
+	if err != nil {
+		return false, err
+	}
+
+	dir, err := os.MkdirTemp(privateTempDir(), "jnal-edit-*")
+	if err != nil {
+		return false, fmt.Errorf("creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Keep the .md name so that editors recognize Markdown
+	tmpPath := filepath.Join(dir, filepath.Base(strings.TrimSuffix(entryPath, EncryptedExt)))
+	defer crypt.SecureRemove(tmpPath)
+	if err := os.WriteFile(tmpPath, plaintext, 0600); err != nil {
+		return false, fmt.Errorf("writing temporary file: %w", err)
+	}
+
+	if err := edit(tmpPath); err != nil {
+		return false, err
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return false, fmt.Errorf("reading temporary file: %w", err)
+	}
+	if bytes.Equal(edited, plaintext) {
+		return false, nil
+	}
+	if err := j.WriteEntry(entryPath, edited); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// privateTempDir returns the directory for decrypted temporary files, preferring
+// the per-user runtime directory, which is usually memory-backed
+func privateTempDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return os.TempDir()
+}
@@ -0,0 +1,160 @@
+package jnal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/longkey1/jnal/internal/crypt"
+)
+
+// newEncryptedJournal creates a journal with encryption enabled and a new identity file
+func newEncryptedJournal(t *testing.T) *Journal {
+	t.Helper()
+	j := newTestJournal(t, "2006/2006-01-02.md")
+
+	data, _, err := crypt.GenerateIdentityFile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	identityFile := filepath.Join(t.TempDir(), "identity.txt")
+	if err := os.WriteFile(identityFile, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	j.cfg.Encryption.Enabled = true
+	j.cfg.Encryption.IdentityFile = identityFile
+	return j
+}
+
+func TestIsEntryFile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "2024-01-15.md", want: true},
+		{name: "2024-01-15.md.age", want: true},
+		{name: "2024-01-15.txt.age", want: false},
+		{name: "key.age", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsEntryFile(tt.name); got != tt.want {
+			t.Errorf("IsEntryFile(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNumberedPath_Encrypted(t *testing.T) {
+	if got, want := numberedPath("/j/2024-01-15.md.age", 2), "/j/2024-01-15-2.md.age"; got != want {
+		t.Errorf("numberedPath() = %q, want %q", got, want)
+	}
+}
+
+func TestJournal_EncryptedEntry(t *testing.T) {
+	j := newEncryptedJournal(t)
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	entryPath, err := j.CreateEntry(date)
+	if err != nil {
+		t.Fatalf("CreateEntry() error = %v", err)
+	}
+	if !strings.HasSuffix(entryPath, "2024-01-15.md.age") {
+		t.Fatalf("CreateEntry() = %s, want an encrypted entry", entryPath)
+	}
+
+	if err := j.AppendEntry(date, "health note"); err != nil {
+		t.Fatalf("AppendEntry() error = %v", err)
+	}
+
+	raw, err := os.ReadFile(entryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("health note")) {
+		t.Error("entry file contains plaintext")
+	}
+
+	entries, err := j.ListEntries()
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 1 || !entries[0].Date.Equal(date) {
+		t.Fatalf("ListEntries() = %v", entries)
+	}
+
+	data, err := j.ReadEntry(entries[0].Path)
+	if err != nil {
+		t.Fatalf("ReadEntry() error = %v", err)
+	}
+	if !strings.Contains(string(data), "health note") {
+		t.Errorf("ReadEntry() = %q", data)
+	}
+
+	// Entries stay readable when encryption is disabled later
+	j.cfg.Encryption.Enabled = false
+	if got := j.GetEntryPath(date); got != entryPath {
+		t.Errorf("GetEntryPath() = %s, want %s", got, entryPath)
+	}
+}
+
+func TestJournal_EditEntry_Encrypted(t *testing.T) {
+	j := newEncryptedJournal(t)
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	var tmpPath string
+	changed, err := j.EditEntry(date, func(path string) error {
+		tmpPath = path
+		if filepath.Ext(path) != ".md" {
+			t.Errorf("editing %s, want a .md file", path)
+		}
+		return os.WriteFile(path, []byte("edited\n"), 0600)
+	})
+	if err != nil || !changed {
+		t.Fatalf("EditEntry() = %v, %v, want true", changed, err)
+	}
+
+	if _, err := os.Stat(tmpPath); !os.IsNotExist(err) {
+		t.Errorf("temporary file not removed: %v", err)
+	}
+	data, err := j.ReadEntry(j.GetEntryPath(date))
+	if err != nil || string(data) != "edited\n" {
+		t.Errorf("ReadEntry() = %q, %v", data, err)
+	}
+
+	changed, err = j.EditEntry(date, func(string) error { return nil })
+	if err != nil || changed {
+		t.Errorf("EditEntry() without changes = %v, %v, want false", changed, err)
+	}
+}
+
+func TestJournal_EncryptDecryptEntries(t *testing.T) {
+	j := newEncryptedJournal(t)
+	plain := filepath.Join(j.GetBaseDir(), "2024", "2024-01-15.md")
+	writeTestFile(t, plain, "plain entry\n")
+
+	encrypted, err := j.EncryptEntries()
+	if err != nil {
+		t.Fatalf("EncryptEntries() error = %v", err)
+	}
+	if len(encrypted) != 1 || encrypted[0] != plain+EncryptedExt {
+		t.Fatalf("EncryptEntries() = %v", encrypted)
+	}
+	if _, err := os.Stat(plain); !os.IsNotExist(err) {
+		t.Errorf("plain entry not removed: %v", err)
+	}
+
+	decrypted, err := j.DecryptEntries()
+	if err != nil {
+		t.Fatalf("DecryptEntries() error = %v", err)
+	}
+	if len(decrypted) != 1 || decrypted[0] != plain {
+		t.Fatalf("DecryptEntries() = %v", decrypted)
+	}
+	if data, err := os.ReadFile(plain); err != nil || string(data) != "plain entry\n" {
+		t.Errorf("decrypted entry = %q, %v", data, err)
+	}
+}
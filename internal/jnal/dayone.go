@@ -0,0 +1,367 @@
+package jnal
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Strategies for importing multiple Day One entries written on the same day
+const (
+	// MergeAppend writes one file per day with a time heading per entry
+	MergeAppend = "append"
+	// MergeSeparate writes one file per entry, numbering the files after the first
+	MergeSeparate = "separate"
+)
+
+// MergeStrategies lists the supported same-day merge strategies
+var MergeStrategies = []string{MergeAppend, MergeSeparate}
+
+// DayOneOptions represents options for importing a Day One export
+type DayOneOptions struct {
+	// Merge is the strategy for multiple entries on the same day
+	Merge string
+}
+
+// dayOneExport is the JSON document of a Day One journal export
+type dayOneExport struct {
+	Entries []dayOneEntry `json:"entries"`
+}
+
+// dayOneEntry is an entry of a Day One export
+type dayOneEntry struct {
+	CreationDate time.Time       `json:"creationDate"`
+	TimeZone     string          `json:"timeZone"`
+	Text         string          `json:"text"`
+	Tags         []string        `json:"tags"`
+	Starred      bool            `json:"starred"`
+	Location     *dayOneLocation `json:"location"`
+	Weather      *dayOneWeather  `json:"weather"`
+	Photos       []dayOnePhoto   `json:"photos"`
+}
+
+// dayOneLocation is the location of a Day One entry
+type dayOneLocation struct {
+	PlaceName          string  `json:"placeName"`
+	LocalityName       string  `json:"localityName"`
+	AdministrativeArea string  `json:"administrativeArea"`
+	Country            string  `json:"country"`
+	Latitude           float64 `json:"latitude"`
+	Longitude          float64 `json:"longitude"`
+}
+
+// dayOneWeather is the weather of a Day One entry
+type dayOneWeather struct {
+	ConditionsDescription string   `json:"conditionsDescription"`
+	TemperatureCelsius    *float64 `json:"temperatureCelsius"`
+}
+
+// dayOnePhoto is a photo of a Day One entry, stored as photos/<md5>.<type> in the export
+type dayOnePhoto struct {
+	Identifier string `json:"identifier"`
+	MD5        string `json:"md5"`
+	Type       string `json:"type"`
+}
+
+// fileName returns the name of the photo file in the export
+func (p dayOnePhoto) fileName() string {
+	return p.MD5 + "." + p.Type
+}
+
+// dayOneFrontMatter is the front matter written for imported Day One entries
+type dayOneFrontMatter struct {
+	Tags     []string             `yaml:"tags,omitempty,flow"`
+	Starred  bool                 `yaml:"starred,omitempty"`
+	Location *locationFrontMatter `yaml:"location,omitempty"`
+	Weather  *weatherFrontMatter  `yaml:"weather,omitempty"`
+}
+
+// locationFrontMatter is the location of an entry in front matter
+type locationFrontMatter struct {
+	Place     string  `yaml:"place,omitempty"`
+	Locality  string  `yaml:"locality,omitempty"`
+	Region    string  `yaml:"region,omitempty"`
+	Country   string  `yaml:"country,omitempty"`
+	Latitude  float64 `yaml:"latitude,omitempty"`
+	Longitude float64 `yaml:"longitude,omitempty"`
+}
+
+// weatherFrontMatter is the weather of an entry in front matter
+type weatherFrontMatter struct {
+	Conditions         string   `yaml:"conditions,omitempty"`
+	TemperatureCelsius *float64 `yaml:"temperature_celsius,omitempty"`
+}
+
+// dayOneMomentPattern matches photo references in Day One Markdown
+var dayOneMomentPattern = regexp.MustCompile(`!\[[^\]]*\]\(dayone-moment://([A-Za-z0-9]+)\)`)
+
+// dayOneImport holds the state of a running Day One import
+type dayOneImport struct {
+	j      *Journal
+	photos fs.FS
+	tmpDir string
+	result *ImportResult
+}
+
+// ImportDayOne imports a Day One JSON export, either the exported zip file or
+// an extracted JSON file with its photos directory next to it. Entries are
+// written to GetEntryPath of their local date; existing files are never overwritten.
+func (j *Journal) ImportDayOne(src string, opts DayOneOptions) (*ImportResult, error) {
+	if !slices.Contains(MergeStrategies, opts.Merge) {
+		return nil, fmt.Errorf("invalid merge strategy: %s (must be one of: %s)", opts.Merge, strings.Join(MergeStrategies, ", "))
+	}
+
+	entries, photos, closeExport, err := openDayOne(src)
+	if err != nil {
+		return nil, err
+	}
+	defer closeExport()
+
+	tmpDir, err := os.MkdirTemp("", "jnal-dayone-")
+	if err != nil {
+		return nil, fmt.Errorf("creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	imp := &dayOneImport{j: j, photos: photos, tmpDir: tmpDir, result: &ImportResult{}}
+
+	// Group entries by their local date
+	days := make(map[time.Time][]dayOneEntry)
+	for _, e := range entries {
+		date := dayOneDate(e)
+		days[date] = append(days[date], e)
+	}
+	dates := make([]time.Time, 0, len(days))
+	for d := range days {
+		dates = append(dates, d)
+	}
+	slices.SortFunc(dates, time.Time.Compare)
+
+	for _, date := range dates {
+		dayEntries := days[date]
+		slices.SortStableFunc(dayEntries, func(a, b dayOneEntry) int {
+			return a.CreationDate.Compare(b.CreationDate)
+		})
+
+		if opts.Merge == MergeSeparate {
+			for i, e := range dayEntries {
+				if err := imp.write(date, numberedPath(j.GetEntryPath(date), i+1), []dayOneEntry{e}); err != nil {
+					return imp.result, err
+				}
+			}
+			continue
+		}
+		if err := imp.write(date, j.GetEntryPath(date), dayEntries); err != nil {
+			return imp.result, err
+		}
+	}
+
+	return imp.result, nil
+}
+
+// write writes the entries to a new entry file. Multiple entries get time headings.
+func (imp *dayOneImport) write(date time.Time, entryPath string, entries []dayOneEntry) error {
+	if _, err := os.Lstat(entryPath); err == nil {
+		imp.result.Skipped = append(imp.result.Skipped, ImportedFile{Path: entryPath})
+		return nil
+	}
+
+	fm := &dayOneFrontMatter{}
+	var bodies []string
+	for _, e := range entries {
+		mergeDayOneFrontMatter(fm, e)
+
+		text, err := imp.replacePhotos(date, e)
+		if err != nil {
+			return err
+		}
+		text = strings.TrimSpace(text)
+		if len(entries) > 1 {
+			text = strings.TrimSpace("## " + e.CreationDate.In(dayOneLocationOf(e)).Format("15:04") + "\n\n" + text)
+		}
+		bodies = append(bodies, text)
+	}
+
+	var content string
+	if len(fm.Tags) > 0 || fm.Starred || fm.Location != nil || fm.Weather != nil {
+		var err error
+		if content, err = MarshalFrontMatter(fm); err != nil {
+			return err
+		}
+	}
+	content += strings.Join(bodies, "\n\n") + "\n"
+
+	if err := imp.j.writeNewEntry(entryPath, content); err != nil {
+		return err
+	}
+	imp.result.Created = append(imp.result.Created, ImportedFile{Path: entryPath})
+	return nil
+}
+
+// replacePhotos copies the photos of the entry into the attachments directory and
+// replaces their references in the text. Photos not referenced are appended.
+func (imp *dayOneImport) replacePhotos(date time.Time, e dayOneEntry) (string, error) {
+	links := make(map[string]string)
+	for _, p := range e.Photos {
+		link, err := imp.attachPhoto(date, p)
+		if err != nil {
+			return "", err
+		}
+		if link != "" {
+			links[p.Identifier] = link
+		}
+	}
+
+	referenced := make(map[string]bool)
+	text := dayOneMomentPattern.ReplaceAllStringFunc(e.Text, func(m string) string {
+		id := dayOneMomentPattern.FindStringSubmatch(m)[1]
+		link, ok := links[id]
+		if !ok {
+			return m
+		}
+		referenced[id] = true
+		return link
+	})
+
+	for _, p := range e.Photos {
+		if link, ok := links[p.Identifier]; ok && !referenced[p.Identifier] {
+			text = strings.TrimRight(text, "\n") + "\n\n" + link
+		}
+	}
+	return text, nil
+}
+
+// attachPhoto copies a photo into the attachments directory and returns its link,
+// or an empty string if the export doesn't contain the photo
+func (imp *dayOneImport) attachPhoto(date time.Time, p dayOnePhoto) (string, error) {
+	name := p.fileName()
+	if p.MD5 == "" || p.Type == "" || name != path.Base(name) {
+		imp.result.Warnings = append(imp.result.Warnings, fmt.Sprintf("photo %s has no file", p.Identifier))
+		return "", nil
+	}
+
+	src, err := imp.photos.Open(path.Join("photos", name))
+	if err != nil {
+		imp.result.Warnings = append(imp.result.Warnings, fmt.Sprintf("photo %s not found in export", name))
+		return "", nil
+	}
+	defer src.Close()
+
+	tmpPath := filepath.Join(imp.tmpDir, name)
+	if err := writeNewFileFrom(tmpPath, src); err != nil {
+		return "", fmt.Errorf("extracting photo %s: %w", name, err)
+	}
+
+	attachment, err := imp.j.storeAttachment(date, tmpPath, AttachOptions{Move: true})
+	if err != nil {
+		return "", err
+	}
+	if !attachment.Reused {
+		imp.result.Attachments++
+	}
+	return attachment.Link, nil
+}
+
+// openDayOne reads the entries of a Day One export and returns the file system holding its photos
+func openDayOne(src string) ([]dayOneEntry, fs.FS, func(), error) {
+	if strings.EqualFold(filepath.Ext(src), ".json") {
+		entries, err := readDayOneJSON(os.DirFS(filepath.Dir(src)), filepath.Base(src))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return entries, os.DirFS(filepath.Dir(src)), func() {}, nil
+	}
+
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("opening %s: %w", src, err)
+	}
+
+	// An export contains one JSON file per journal
+	var entries []dayOneEntry
+	found := false
+	for _, f := range zr.File {
+		if strings.Contains(f.Name, "/") || !strings.EqualFold(path.Ext(f.Name), ".json") {
+			continue
+		}
+		found = true
+		journal, err := readDayOneJSON(zr, f.Name)
+		if err != nil {
+			zr.Close()
+			return nil, nil, nil, err
+		}
+		entries = append(entries, journal...)
+	}
+	if !found {
+		zr.Close()
+		return nil, nil, nil, fmt.Errorf("no journal JSON found in %s", src)
+	}
+
+	return entries, zr, func() { zr.Close() }, nil
+}
+
+// readDayOneJSON reads the entries of a Day One journal JSON file
+func readDayOneJSON(fsys fs.FS, name string) ([]dayOneEntry, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+
+	var export dayOneExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
+	}
+	return export.Entries, nil
+}
+
+// mergeDayOneFrontMatter adds the metadata of the entry to the front matter.
+// Tags are merged, and the first location and weather of the day are kept.
+func mergeDayOneFrontMatter(fm *dayOneFrontMatter, e dayOneEntry) {
+	for _, tag := range e.Tags {
+		if !slices.Contains(fm.Tags, tag) {
+			fm.Tags = append(fm.Tags, tag)
+		}
+	}
+	fm.Starred = fm.Starred || e.Starred
+
+	if fm.Location == nil && e.Location != nil {
+		fm.Location = &locationFrontMatter{
+			Place:     e.Location.PlaceName,
+			Locality:  e.Location.LocalityName,
+			Region:    e.Location.AdministrativeArea,
+			Country:   e.Location.Country,
+			Latitude:  e.Location.Latitude,
+			Longitude: e.Location.Longitude,
+		}
+	}
+	if fm.Weather == nil && e.Weather != nil {
+		fm.Weather = &weatherFrontMatter{
+			Conditions:         e.Weather.ConditionsDescription,
+			TemperatureCelsius: e.Weather.TemperatureCelsius,
+		}
+	}
+}
+
+// dayOneDate returns the date the entry was written in its own time zone
+func dayOneDate(e dayOneEntry) time.Time {
+	local := e.CreationDate.In(dayOneLocationOf(e))
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// dayOneLocationOf returns the time zone the entry was written in, defaulting to local time
+func dayOneLocationOf(e dayOneEntry) *time.Location {
+	if e.TimeZone != "" {
+		if loc, err := time.LoadLocation(e.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
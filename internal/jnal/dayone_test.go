@@ -0,0 +1,186 @@
+package jnal
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const dayOneJSON = `{
+  "metadata": {"version": "1.0"},
+  "entries": [
+    {
+      "creationDate": "2024-01-15T09:30:00Z",
+      "timeZone": "UTC",
+      "text": "Morning\n\n![](dayone-moment://PHOTO1)\n\nCoffee",
+      "tags": ["work"],
+      "starred": true,
+      "location": {"placeName": "Office", "localityName": "Tokyo", "country": "Japan", "latitude": 35.68, "longitude": 139.76},
+      "weather": {"conditionsDescription": "Cloudy", "temperatureCelsius": 8.5},
+      "photos": [{"identifier": "PHOTO1", "md5": "abc123", "type": "jpeg"}]
+    },
+    {
+      "creationDate": "2024-01-15T20:00:00Z",
+      "timeZone": "UTC",
+      "text": "Evening",
+      "tags": ["work", "family"]
+    },
+    {
+      "creationDate": "2024-01-16T23:30:00Z",
+      "timeZone": "Asia/Tokyo",
+      "text": "Written on the 17th in Tokyo"
+    }
+  ]
+}`
+
+// writeDayOneZip writes a Day One export with a journal and one photo
+func writeDayOneZip(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "export.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"Journal.json":       dayOneJSON,
+		"photos/abc123.jpeg": "jpeg",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestJournal_ImportDayOne(t *testing.T) {
+	t.Run("append", func(t *testing.T) {
+		j := newTestJournal(t, "2006/2006-01-02.md")
+		src := writeDayOneZip(t)
+
+		result, err := j.ImportDayOne(src, DayOneOptions{Merge: MergeAppend})
+		if err != nil {
+			t.Fatalf("ImportDayOne() error = %v", err)
+		}
+		if len(result.Created) != 2 || result.Attachments != 1 || len(result.Warnings) != 0 {
+			t.Fatalf("ImportDayOne() = %+v", result)
+		}
+
+		data, err := os.ReadFile(j.GetEntryPath(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `---
+tags: [work, family]
+starred: true
+location:
+  place: Office
+  locality: Tokyo
+  country: Japan
+  latitude: 35.68
+  longitude: 139.76
+weather:
+  conditions: Cloudy
+  temperature_celsius: 8.5
+---
+## 09:30
+
+Morning
+
+![abc123.jpeg](attachments/2024-01-15/abc123.jpeg)
+
+Coffee
+
+## 20:00
+
+Evening
+`
+		if string(data) != want {
+			t.Errorf("entry = %q, want %q", data, want)
+		}
+		if _, err := os.Stat(filepath.Join(j.GetBaseDir(), "2024", "attachments", "2024-01-15", "abc123.jpeg")); err != nil {
+			t.Errorf("photo not copied: %v", err)
+		}
+
+		// The entry's own time zone decides the date
+		data, err = os.ReadFile(j.GetEntryPath(time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "Written on the 17th in Tokyo\n" {
+			t.Errorf("entry = %q", data)
+		}
+
+		// Importing again leaves existing entries alone
+		result, err = j.ImportDayOne(src, DayOneOptions{Merge: MergeAppend})
+		if err != nil {
+			t.Fatalf("ImportDayOne() error = %v", err)
+		}
+		if len(result.Created) != 0 || len(result.Skipped) != 2 {
+			t.Errorf("ImportDayOne() again = %+v, want all skipped", result)
+		}
+	})
+
+	t.Run("separate", func(t *testing.T) {
+		j := newTestJournal(t, "2006/2006-01-02.md")
+		result, err := j.ImportDayOne(writeDayOneZip(t), DayOneOptions{Merge: MergeSeparate})
+		if err != nil {
+			t.Fatalf("ImportDayOne() error = %v", err)
+		}
+		if len(result.Created) != 3 {
+			t.Fatalf("ImportDayOne() created %v, want 3 files", result.Created)
+		}
+
+		data, err := os.ReadFile(filepath.Join(j.GetBaseDir(), "2024", "2024-01-15-2.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "---\ntags: [work, family]\n---\nEvening\n" {
+			t.Errorf("second entry = %q", data)
+		}
+
+		entries, err := j.ListEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 3 {
+			t.Errorf("ListEntries() = %d entries, want 3", len(entries))
+		}
+	})
+
+	t.Run("json with missing photo", func(t *testing.T) {
+		j := newTestJournal(t, "2006/2006-01-02.md")
+		src := filepath.Join(t.TempDir(), "Journal.json")
+		if err := os.WriteFile(src, []byte(dayOneJSON), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := j.ImportDayOne(src, DayOneOptions{Merge: MergeAppend})
+		if err != nil {
+			t.Fatalf("ImportDayOne() error = %v", err)
+		}
+		if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "abc123.jpeg") {
+			t.Errorf("ImportDayOne() warnings = %v, want missing photo", result.Warnings)
+		}
+	})
+
+	t.Run("invalid merge", func(t *testing.T) {
+		j := newTestJournal(t, "2006/2006-01-02.md")
+		if _, err := j.ImportDayOne("export.zip", DayOneOptions{Merge: "zip"}); err == nil {
+			t.Error("ImportDayOne() expected error for invalid merge strategy")
+		}
+	})
+}
@@ -1,7 +1,11 @@
 package jnal
 
 import (
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/longkey1/jnal/internal/util"
 )
 
 // Entry represents a journal entry
@@ -9,6 +13,11 @@ type Entry struct {
 	Path    string
 	Date    time.Time
 	Content string
+	Words   int
+	// Private is set for entries marked private in their front matter
+	Private bool
+	// Publish is the publishing metadata from the front matter
+	Publish PublishStatus
 }
 
 // Entries is a slice of Entry
@@ -16,22 +25,31 @@ type Entries []Entry
 
 // SortByDateDesc sorts entries by date in descending order (newest first)
 func (e Entries) SortByDateDesc() {
-	for i := 0; i < len(e)-1; i++ {
-		for j := i + 1; j < len(e); j++ {
-			if e[j].Date.After(e[i].Date) {
-				e[i], e[j] = e[j], e[i]
-			}
-		}
-	}
+	slices.SortStableFunc(e, func(a, b Entry) int {
+		return compareEntries(b, a)
+	})
 }
 
 // SortByDateAsc sorts entries by date in ascending order (oldest first)
 func (e Entries) SortByDateAsc() {
-	for i := 0; i < len(e)-1; i++ {
-		for j := i + 1; j < len(e); j++ {
-			if e[j].Date.Before(e[i].Date) {
-				e[i], e[j] = e[j], e[i]
-			}
+	slices.SortStableFunc(e, compareEntries)
+}
+
+// Filter returns the entries whose date is within the range
+func (e Entries) Filter(r util.DateRange) Entries {
+	var filtered Entries
+	for _, entry := range e {
+		if r.Contains(entry.Date) {
+			filtered = append(filtered, entry)
 		}
 	}
+	return filtered
+}
+
+// compareEntries compares entries by date, then by path for entries on the same date
+func compareEntries(a, b Entry) int {
+	if c := a.Date.Compare(b.Date); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Path, b.Path)
 }
@@ -0,0 +1,58 @@
+package jnal
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEntries_Sort(t *testing.T) {
+	jan14 := time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)
+	jan15 := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	entries := Entries{
+		{Path: "2024/2024-01-15-2.md", Date: jan15},
+		{Path: "2024/2024-01-14.md", Date: jan14},
+		{Path: "2024/2024-01-15-3.md", Date: jan15},
+		{Path: "2024/2024-01-15.md", Date: jan15},
+	}
+
+	tests := []struct {
+		name string
+		sort func(Entries)
+		want []string
+	}{
+		{
+			name: "descending",
+			sort: Entries.SortByDateDesc,
+			want: []string{"2024/2024-01-15.md", "2024/2024-01-15-3.md", "2024/2024-01-15-2.md", "2024/2024-01-14.md"},
+		},
+		{
+			name: "ascending",
+			sort: Entries.SortByDateAsc,
+			want: []string{"2024/2024-01-14.md", "2024/2024-01-15-2.md", "2024/2024-01-15-3.md", "2024/2024-01-15.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The order must not depend on the initial order
+			for _, initial := range []Entries{slices.Clone(entries), reversed(entries)} {
+				tt.sort(initial)
+				var got []string
+				for _, e := range initial {
+					got = append(got, e.Path)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("sorted = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// reversed returns a reversed copy of the entries
+func reversed(entries Entries) Entries {
+	r := slices.Clone(entries)
+	slices.Reverse(r)
+	return r
+}
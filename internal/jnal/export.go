@@ -0,0 +1,197 @@
+package jnal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/longkey1/jnal/internal/util"
+)
+
+// Export formats
+const (
+	ExportMarkdown = "md"
+	ExportJSON     = "json"
+	ExportJSONL    = "jsonl"
+	ExportCSV      = "csv"
+)
+
+// ExportFormats lists the supported export formats
+var ExportFormats = []string{ExportMarkdown, ExportJSON, ExportJSONL, ExportCSV}
+
+// ExportOptions represents options for exporting entries
+type ExportOptions struct {
+	Format string
+	// Title is the top-level heading of Markdown exports
+	Title string
+	// HeadingShift shifts the headings of entries in Markdown exports
+	HeadingShift int
+}
+
+// ExportEntry represents an entry in JSON and CSV exports
+type ExportEntry struct {
+	Date string `json:"date"`
+	// Path is relative to the base directory
+	Path        string         `json:"path"`
+	Words       int            `json:"words"`
+	Content     string         `json:"content"`
+	FrontMatter map[string]any `json:"front_matter,omitempty"`
+}
+
+// Export writes the entries within the range to w in chronological order
+func (j *Journal) Export(w io.Writer, r util.DateRange, opts ExportOptions) error {
+	entries, err := j.ListEntries()
+	if err != nil {
+		return fmt.Errorf("listing entries: %w", err)
+	}
+	entries = entries.Filter(r)
+	entries.SortByDateAsc()
+
+	exported := make([]ExportEntry, 0, len(entries))
+	for _, e := range entries {
+		data, err := j.ReadEntry(e.Path)
+		if err != nil {
+			return fmt.Errorf("reading entry %s: %w", e.Path, err)
+		}
+		fm, body := ParseFrontMatter(string(data))
+
+		path := e.Path
+		if rel, err := filepath.Rel(j.GetBaseDir(), e.Path); err == nil {
+			path = filepath.ToSlash(rel)
+		}
+		exported = append(exported, ExportEntry{
+			Date:        util.Format(e.Date),
+			Path:        path,
+			Words:       CountWords(body),
+			Content:     body,
+			FrontMatter: fm,
+		})
+	}
+
+	return WriteExport(w, exported, opts)
+}
+
+// WriteExport writes entries in the format given in opts
+func WriteExport(w io.Writer, entries []ExportEntry, opts ExportOptions) error {
+	switch opts.Format {
+	case ExportMarkdown:
+		return writeMarkdownExport(w, entries, opts)
+	case ExportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case ExportJSONL:
+		enc := json.NewEncoder(w)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ExportCSV:
+		return writeCSVExport(w, entries)
+	default:
+		return fmt.Errorf("invalid format: %s (must be one of: %s)", opts.Format, strings.Join(ExportFormats, ", "))
+	}
+}
+
+// writeMarkdownExport writes entries as a single Markdown document with the same
+// heading structure as the HTML output: title, year, month, date, then the entry
+func writeMarkdownExport(w io.Writer, entries []ExportEntry, opts ExportOptions) error {
+	var sb strings.Builder
+	if opts.Title != "" {
+		sb.WriteString("# " + opts.Title + "\n")
+	}
+
+	year, month := "", ""
+	for _, e := range entries {
+		if y := e.Date[:4]; y != year {
+			year, month = y, ""
+			sb.WriteString("\n## " + year + "\n")
+		}
+		if m := e.Date[:7]; m != month {
+			month = m
+			sb.WriteString("\n### " + month + "\n")
+		}
+		sb.WriteString("\n#### " + e.Date + "\n\n")
+
+		content := strings.TrimSpace(ShiftMarkdownHeadings(e.Content, opts.HeadingShift))
+		if content != "" {
+			sb.WriteString(content + "\n")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeCSVExport writes entries as CSV with a header row.
+// Front matter is encoded as JSON in its own column.
+func writeCSVExport(w io.Writer, entries []ExportEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "path", "words", "content", "front_matter"}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		fm := ""
+		if len(e.FrontMatter) > 0 {
+			data, err := json.Marshal(e.FrontMatter)
+			if err != nil {
+				return fmt.Errorf("encoding front matter of %s: %w", e.Path, err)
+			}
+			fm = string(data)
+		}
+		if err := cw.Write([]string{e.Date, e.Path, strconv.Itoa(e.Words), e.Content, fm}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ShiftMarkdownHeadings shifts ATX heading levels in Markdown by the specified amount,
+// leaving fenced code blocks untouched. Headings are clamped to level 6.
+func ShiftMarkdownHeadings(content string, shift int) string {
+	if shift <= 0 {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+
+		if fence != "" {
+			if closesFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if f := openingFence(line); f != "" {
+			fence = f
+			continue
+		}
+		if indent > 3 {
+			continue
+		}
+
+		level := 0
+		for level < len(trimmed) && trimmed[level] == '#' {
+			level++
+		}
+		if level == 0 || level > 6 {
+			continue
+		}
+		if level < len(trimmed) && trimmed[level] != ' ' && trimmed[level] != '\t' && trimmed[level] != '\r' {
+			continue
+		}
+		newLevel := min(level+shift, 6)
+		lines[i] = line[:indent] + strings.Repeat("#", newLevel) + trimmed[level:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,133 @@
+package jnal
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/longkey1/jnal/internal/util"
+)
+
+func TestShiftMarkdownHeadings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		shift   int
+		want    string
+	}{
+		{name: "no shift", content: "# A", shift: 0, want: "# A"},
+		{name: "shift", content: "# A\n## B\ntext", shift: 2, want: "### A\n#### B\ntext"},
+		{name: "clamp", content: "# A\n#### B", shift: 4, want: "##### A\n###### B"},
+		{name: "indented", content: "  # A", shift: 1, want: "  ## A"},
+		{name: "empty heading", content: "#", shift: 1, want: "##"},
+		{name: "not a heading", content: "#tag\n    # code", shift: 1, want: "#tag\n    # code"},
+		{name: "fenced code", content: "```sh\n# comment\n```\n# A", shift: 1, want: "```sh\n# comment\n```\n## A"},
+		{name: "longer fence", content: "````\n```\n# comment\n````\n# A", shift: 1, want: "````\n```\n# comment\n````\n## A"},
+		{name: "tilde fence", content: "~~~\n# comment\n~~~", shift: 1, want: "~~~\n# comment\n~~~"},
+		{name: "inline code", content: "```go``` code\n# A", shift: 1, want: "```go``` code\n## A"},
+		{name: "indented fence", content: "    ```\n# A", shift: 1, want: "    ```\n## A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShiftMarkdownHeadings(tt.content, tt.shift); got != tt.want {
+				t.Errorf("ShiftMarkdownHeadings() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJournal_Export(t *testing.T) {
+	j := newTestJournal(t, "2006/2006-01-02.md")
+	files := map[string]string{
+		"2024/2024-01-15.md": "---\ntags: [work]\n---\n# Retro\n\nShipped it.\n",
+		"2024/2024-01-03.md": "New year, new journal.\n",
+		"2023/2023-12-31.md": "Last day.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(j.GetBaseDir(), name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := util.ParseRange("2024-01-01", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := j.Export(&buf, r, ExportOptions{Format: ExportMarkdown, Title: "Journal", HeadingShift: 4}); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		want := "# Journal\n\n## 2024\n\n### 2024-01\n\n#### 2024-01-03\n\nNew year, new journal.\n\n#### 2024-01-15\n\n##### Retro\n\nShipped it.\n"
+		if got := buf.String(); got != want {
+			t.Errorf("Export() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := j.Export(&buf, r, ExportOptions{Format: ExportJSON}); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		var got []ExportEntry
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("Export() returned %d entries, want 2", len(got))
+		}
+		if got[0].Date != "2024-01-03" || got[1].Path != "2024/2024-01-15.md" {
+			t.Errorf("Export() = %+v, want chronological entries with relative paths", got)
+		}
+		if got[1].Content != "# Retro\n\nShipped it.\n" {
+			t.Errorf("Export() content = %q, want body without front matter", got[1].Content)
+		}
+		if tags, ok := got[1].FrontMatter["tags"].([]any); !ok || len(tags) != 1 || tags[0] != "work" {
+			t.Errorf("Export() front matter = %v, want tags [work]", got[1].FrontMatter)
+		}
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := j.Export(&buf, r, ExportOptions{Format: ExportJSONL}); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Export() wrote %d lines, want 2", len(lines))
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := j.Export(&buf, r, ExportOptions{Format: ExportCSV}); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		records, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid CSV: %v", err)
+		}
+		if len(records) != 3 {
+			t.Fatalf("Export() wrote %d records, want 3", len(records))
+		}
+		if got := records[2]; got[0] != "2024-01-15" || got[2] != "4" || got[4] != `{"tags":["work"]}` {
+			t.Errorf("Export() record = %q", got)
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		if err := j.Export(&bytes.Buffer{}, r, ExportOptions{Format: "xml"}); err == nil {
+			t.Error("Export() expected error for invalid format")
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package jnal
+
+import "strings"
+
+// openingFence returns the fence of the fenced code block opened by line, or "" if line
+// doesn't open one. Following CommonMark, a fence is a run of at least three backticks or
+// tildes indented by at most three spaces, and the info string after a backtick fence
+// contains no backtick, so that inline code at the start of a line is not a fence.
+func openingFence(line string) string {
+	trimmed, ok := fenceIndent(line)
+	if !ok {
+		return ""
+	}
+	for _, c := range []byte{'`', '~'} {
+		n := 0
+		for n < len(trimmed) && trimmed[n] == c {
+			n++
+		}
+		if n < 3 {
+			continue
+		}
+		if c == '`' && strings.Contains(trimmed[n:], "`") {
+			return ""
+		}
+		return trimmed[:n]
+	}
+	return ""
+}
+
+// closesFence reports whether line closes the fenced code block opened by fence: a run of
+// the same character at least as long, indented by at most three spaces, and nothing else
+func closesFence(line, fence string) bool {
+	trimmed, ok := fenceIndent(line)
+	if !ok || fence == "" {
+		return false
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == fence[0] {
+		n++
+	}
+	return n >= len(fence) && strings.TrimRight(trimmed[n:], " \t") == ""
+}
+
+// fenceIndent returns the line without its indentation and line ending, and false if it is
+// indented by more than three spaces, which makes it part of an indented code block
+func fenceIndent(line string) (string, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	trimmed := strings.TrimLeft(line, " ")
+	return trimmed, len(line)-len(trimmed) <= 3
+}
@@ -0,0 +1,69 @@
+package jnal
+
+import (
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// frontMatterDelimiter opens and closes YAML front matter
+const frontMatterDelimiter = "---"
+
+// ParseFrontMatter splits YAML front matter delimited by "---" lines off the content.
+// Content without front matter is returned unchanged with a nil map.
+func ParseFrontMatter(content string) (map[string]any, string) {
+	fm, body, _ := splitFrontMatter(content)
+	return fm, body
+}
+
+// splitFrontMatter returns the decoded front matter and the body following it.
+// Only a block that decodes to a YAML mapping is front matter, so that an entry
+// starting with a "---" thematic break keeps its content.
+func splitFrontMatter(content string) (map[string]any, string, bool) {
+	first, rest, found := strings.Cut(content, "\n")
+	if !found || strings.TrimRight(first, " \t\r") != frontMatterDelimiter {
+		return nil, content, false
+	}
+
+	offset := 0
+	for offset <= len(rest) {
+		line, next, more := strings.Cut(rest[offset:], "\n")
+		if trimmed := strings.TrimRight(line, " \t\r"); trimmed == frontMatterDelimiter || trimmed == "..." {
+			fm := make(map[string]any)
+			if err := yaml.Unmarshal([]byte(rest[:offset]), &fm); err != nil {
+				return nil, content, false
+			}
+			body := ""
+			if more {
+				body = next
+			}
+			return fm, body, true
+		}
+		if !more {
+			break
+		}
+		offset += len(line) + 1
+	}
+	return nil, content, false
+}
+
+// StripFrontMatter returns the content without its front matter
+func StripFrontMatter(content string) string {
+	_, body, _ := splitFrontMatter(content)
+	return body
+}
+
+// MarshalFrontMatter encodes v as YAML front matter including the delimiters
+func MarshalFrontMatter(v any) (string, error) {
+	var sb strings.Builder
+	enc := yaml.NewEncoder(&sb)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return "", fmt.Errorf("encoding front matter: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("encoding front matter: %w", err)
+	}
+	return frontMatterDelimiter + "\n" + sb.String() + frontMatterDelimiter + "\n", nil
+}
@@ -0,0 +1,74 @@
+package jnal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantFM  map[string]any
+		want    string
+	}{
+		{
+			name:    "no front matter",
+			content: "# Title\n\nBody\n",
+			want:    "# Title\n\nBody\n",
+		},
+		{
+			name:    "front matter",
+			content: "---\ntags: [work, travel]\nstarred: true\n---\n# Title\n",
+			wantFM:  map[string]any{"tags": []any{"work", "travel"}, "starred": true},
+			want:    "# Title\n",
+		},
+		{
+			name:    "dots close",
+			content: "---\nmood: good\n...\nBody",
+			wantFM:  map[string]any{"mood": "good"},
+			want:    "Body",
+		},
+		{
+			name:    "empty front matter",
+			content: "---\n---\nBody",
+			wantFM:  map[string]any{},
+			want:    "Body",
+		},
+		{
+			name:    "unterminated",
+			content: "---\nnot front matter\n",
+			want:    "---\nnot front matter\n",
+		},
+		{
+			name:    "thematic break later",
+			content: "Body\n---\nMore",
+			want:    "Body\n---\nMore",
+		},
+		{
+			name:    "thematic break first",
+			content: "---\nMorning notes\n\n---\nEvening notes\n",
+			want:    "---\nMorning notes\n\n---\nEvening notes\n",
+		},
+		{
+			name:    "invalid yaml",
+			content: "---\ntags: [work\n---\nBody",
+			want:    "---\ntags: [work\n---\nBody",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body := ParseFrontMatter(tt.content)
+			if got := StripFrontMatter(tt.content); got != tt.want {
+				t.Errorf("StripFrontMatter() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(fm, tt.wantFM) {
+				t.Errorf("ParseFrontMatter() front matter = %#v, want %#v", fm, tt.wantFM)
+			}
+			if body != tt.want {
+				t.Errorf("ParseFrontMatter() body = %q, want %q", body, tt.want)
+			}
+		})
+	}
+}
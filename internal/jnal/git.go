@@ -0,0 +1,75 @@
+package jnal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/longkey1/jnal/internal/git"
+	"github.com/longkey1/jnal/internal/util"
+)
+
+// CommitEntry commits changes to the entry for the given date and the paths with the
+// commit_message template if auto_commit is enabled
+func (j *Journal) CommitEntry(date time.Time, paths ...string) error {
+	if !j.cfg.Git.AutoCommit {
+		return nil
+	}
+
+	message, err := j.executeTemplate(j.cfg.Git.CommitMessage, map[string]interface{}{
+		"Date": date.Format(j.cfg.Common.DateFormat),
+		"Env":  getEnvMap(),
+	})
+	if err != nil {
+		return fmt.Errorf("building commit message: %w", err)
+	}
+
+	return j.commit(message, append([]string{j.GetEntryPath(date)}, paths...)...)
+}
+
+// CommitChanges commits all changes in the base directory with the message if
+// auto_commit is enabled. It is used by commands that change many entries.
+func (j *Journal) CommitChanges(message string) error {
+	if !j.cfg.Git.AutoCommit {
+		return nil
+	}
+	return j.commit(message, j.GetBaseDir())
+}
+
+// commit commits changes to the paths
+func (j *Journal) commit(message string, paths ...string) error {
+	repo, err := git.Open(j.GetBaseDir())
+	if err != nil {
+		return err
+	}
+	if _, err := repo.Commit(message, paths...); err != nil {
+		return fmt.Errorf("committing: %w", err)
+	}
+	return nil
+}
+
+// Sync commits all changes in the base directory, pulls with rebase from the remote and pushes
+func (j *Journal) Sync(opts git.SyncOptions) (*git.SyncResult, error) {
+	repo, err := git.Open(j.GetBaseDir())
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Remote == "" {
+		opts.Remote = j.cfg.Git.Remote
+	}
+	if opts.Branch == "" {
+		opts.Branch = j.cfg.Git.Branch
+	}
+	if opts.Message == "" {
+		message, err := j.executeTemplate(j.cfg.Git.SyncMessage, map[string]interface{}{
+			"Date": util.Today().Format(j.cfg.Common.DateFormat),
+			"Env":  getEnvMap(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("building commit message: %w", err)
+		}
+		opts.Message = message
+	}
+
+	return repo.Sync(opts)
+}
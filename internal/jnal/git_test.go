@@ -0,0 +1,49 @@
+package jnal
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournal_CommitEntry(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	j := newTestJournal(t, "2006/2006-01-02.md")
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	if out, err := exec.Command("git", "-C", j.GetBaseDir(), "init", "--quiet").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	if _, err := j.CreateEntry(date); err != nil {
+		t.Fatal(err)
+	}
+
+	// Disabled by default
+	if err := j.CommitEntry(date); err != nil {
+		t.Fatalf("CommitEntry() error = %v", err)
+	}
+	if out, _ := exec.Command("git", "-C", j.GetBaseDir(), "log").CombinedOutput(); !strings.Contains(string(out), "does not have any commits") {
+		t.Fatalf("committed without auto_commit: %s", out)
+	}
+
+	j.cfg.Git.AutoCommit = true
+	if err := j.CommitEntry(date); err != nil {
+		t.Fatalf("CommitEntry() error = %v", err)
+	}
+	out, err := exec.Command("git", "-C", j.GetBaseDir(), "log", "--format=%s", "--name-only").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git log: %v: %s", err, out)
+	}
+	if got := strings.TrimSpace(string(out)); got != "entry: 2024-01-15\n\n2024/2024-01-15.md" {
+		t.Errorf("log = %q", got)
+	}
+}
@@ -0,0 +1,300 @@
+package jnal
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/util"
+)
+
+// importExtensions lists the file extensions imported from directories
+var importExtensions = map[string]bool{
+	".md":       true,
+	".markdown": true,
+	".txt":      true,
+}
+
+// frontMatterDateKeys lists the front matter keys holding the date of a note
+var frontMatterDateKeys = []string{"date", "created"}
+
+// ImportedFile represents an entry file written or skipped by an import
+type ImportedFile struct {
+	// Path is the entry file
+	Path string
+	// Source is the imported file, if the entry comes from a single file
+	Source string
+}
+
+// ImportResult summarizes an import
+type ImportResult struct {
+	// Created lists the entry files written
+	Created []ImportedFile
+	// Appended lists the existing entry files that imported content was appended to
+	Appended []ImportedFile
+	// Skipped lists the entry files that already existed and were left untouched
+	Skipped []ImportedFile
+	// Attachments is the number of files copied into attachment directories
+	Attachments int
+	// Warnings describes problems that didn't stop the import
+	Warnings []string
+}
+
+// FilesOptions represents options for importing a directory of notes
+type FilesOptions struct {
+	// DateSources lists where the date of a file is taken from, in order of priority
+	DateSources []string
+	// Conflict decides what happens when an entry for the date already exists
+	Conflict string
+	// DryRun reports what would be imported without writing anything
+	DryRun bool
+}
+
+// note is a file found by ImportFiles
+type note struct {
+	path    string
+	content string
+	date    time.Time
+}
+
+// ImportFiles imports the Markdown and text files in dir (recursively) as entries,
+// written to GetEntryPath of the date found for each file
+func (j *Journal) ImportFiles(dir string, opts FilesOptions) (*ImportResult, error) {
+	importCfg := config.ImportConfig{DateSources: opts.DateSources, Conflict: opts.Conflict}
+	if err := importCfg.Validate(); err != nil {
+		return nil, err
+	}
+	importCfg.SetDefaults()
+
+	if overlaps(dir, j.GetBaseDir()) {
+		return nil, fmt.Errorf("cannot import from %s: it overlaps the journal directory", dir)
+	}
+
+	result := &ImportResult{}
+	notes, err := findNotes(dir, importCfg.DateSources, result)
+	if err != nil {
+		return nil, err
+	}
+
+	// Files written during a dry run are only planned
+	planned := make(map[string]bool)
+	exists := func(path string) bool {
+		if planned[path] {
+			return true
+		}
+		_, err := os.Lstat(path)
+		return err == nil
+	}
+
+	for _, n := range notes {
+		target := j.GetEntryPath(n.date)
+		imported := ImportedFile{Path: target, Source: n.path}
+
+		if exists(target) {
+			switch importCfg.Conflict {
+			case config.ConflictSkip:
+				result.Skipped = append(result.Skipped, imported)
+				continue
+			case config.ConflictAppend:
+				if !opts.DryRun {
+					if err := j.AppendEntry(n.date, strings.TrimSpace(StripFrontMatter(n.content))); err != nil {
+						return result, fmt.Errorf("importing %s: %w", n.path, err)
+					}
+				}
+				result.Appended = append(result.Appended, imported)
+				continue
+			case config.ConflictRename:
+				i := 2
+				for exists(numberedPath(target, i)) {
+					i++
+				}
+				imported.Path = numberedPath(target, i)
+			}
+		}
+
+		if !opts.DryRun {
+			if err := j.writeNewEntry(imported.Path, n.content); err != nil {
+				return result, fmt.Errorf("importing %s: %w", n.path, err)
+			}
+		}
+		planned[imported.Path] = true
+		result.Created = append(result.Created, imported)
+	}
+
+	return result, nil
+}
+
+// findNotes returns the notes in dir with their dates, sorted by date and path.
+// Files without a date or content are reported as warnings.
+func findNotes(dir string, sources []string, result *ImportResult) ([]note, error) {
+	var notes []note
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Skip hidden files and directories such as .obsidian and .git
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !importExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		content := string(data)
+		if strings.TrimSpace(StripFrontMatter(content)) == "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s is empty", path))
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		date, ok := noteDate(path, content, info, sources)
+		if !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("no date found for %s", path))
+			return nil
+		}
+
+		notes = append(notes, note{path: path, content: content, date: date})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking directory %s: %w", dir, err)
+	}
+
+	slices.SortStableFunc(notes, func(a, b note) int {
+		if c := a.date.Compare(b.date); c != 0 {
+			return c
+		}
+		return strings.Compare(a.path, b.path)
+	})
+	return notes, nil
+}
+
+// noteDate returns the date of a note from the first source that has one
+func noteDate(path, content string, info fs.FileInfo, sources []string) (time.Time, bool) {
+	for _, source := range sources {
+		switch source {
+		case config.DateSourceFilename:
+			if date, err := util.ExtractFromFilename(filepath.Base(path)); err == nil {
+				return date, true
+			}
+		case config.DateSourceFrontMatter:
+			if date, ok := frontMatterDate(content); ok {
+				return date, true
+			}
+		case config.DateSourceHeading:
+			if date, ok := headingDate(content); ok {
+				return date, true
+			}
+		case config.DateSourceMtime:
+			return dateOnly(info.ModTime()), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// frontMatterDate returns the date in the "date" or "created" front matter field
+func frontMatterDate(content string) (time.Time, bool) {
+	fm, _ := ParseFrontMatter(content)
+
+	for _, key := range frontMatterDateKeys {
+		switch v := fm[key].(type) {
+		case time.Time:
+			return dateOnly(v), true
+		case string:
+			if date, ok := findDate(v); ok {
+				return date, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// headingDate returns the date in the first heading of the content
+func headingDate(content string) (time.Time, bool) {
+	for _, line := range strings.Split(StripFrontMatter(content), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+		return findDate(line)
+	}
+	return time.Time{}, false
+}
+
+// findDate returns the first yyyy-mm-dd date in s
+func findDate(s string) (time.Time, bool) {
+	date, err := util.Parse(util.DatePattern.FindString(s))
+	return date, err == nil
+}
+
+// overlaps reports whether one of the directories contains the other
+func overlaps(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return isWithin(absA, absB) || isWithin(absB, absA)
+}
+
+// isWithin reports whether path is dir or inside it
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// numberedPath returns the path for the n-th entry of a day: the path itself for the
+// first, and a numbered file name (2024-01-15-2.md) for the following ones
+func numberedPath(entryPath string, n int) string {
+	if n <= 1 {
+		return entryPath
+	}
+	// Number encrypted entries before the .md extension (2024-01-15-2.md.age)
+	suffix := ""
+	if IsEncrypted(entryPath) {
+		entryPath = strings.TrimSuffix(entryPath, EncryptedExt)
+		suffix = EncryptedExt
+	}
+	ext := filepath.Ext(entryPath)
+	return fmt.Sprintf("%s-%d%s%s", strings.TrimSuffix(entryPath, ext), n, ext, suffix)
+}
+
+// writeNewFile writes content to a new file, creating its directory and failing if the file exists
+func writeNewFile(path, content string) error {
+	return writeNewFileFrom(path, strings.NewReader(content))
+}
+
+// writeNewFileFrom copies r to a new file, creating its directory and failing if the file exists
+func writeNewFileFrom(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), config.DirPermission); err != nil {
+		return fmt.Errorf("creating directory %s: %w", filepath.Dir(path), err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, config.FilePermission)
+	if err != nil {
+		return fmt.Errorf("creating file %s: %w", path, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(path)
+		return fmt.Errorf("writing file %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,164 @@
+package jnal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/longkey1/jnal/internal/config"
+)
+
+func TestNoteDate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "note.md")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all := config.DefaultDateSources
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		sources []string
+		want    string
+		wantOK  bool
+	}{
+		{name: "filename", file: "2024-01-15.md", content: "# 2024-02-01", sources: all, want: "2024-01-15", wantOK: true},
+		{name: "front matter date", file: "note.md", content: "---\ndate: 2024-01-16\n---\n# 2024-02-01", sources: all, want: "2024-01-16", wantOK: true},
+		{name: "front matter string", file: "note.md", content: "---\ncreated: \"2024-01-17T09:00\"\n---\n", sources: all, want: "2024-01-17", wantOK: true},
+		{name: "heading", file: "note.md", content: "intro\n\n# Retro 2024-01-18\n\n## 2024-02-01", sources: all, want: "2024-01-18", wantOK: true},
+		{name: "first heading only", file: "note.md", content: "# Retro\n\n## 2024-02-01", sources: all[2:3], wantOK: false},
+		{name: "mtime", file: "note.md", content: "text", sources: all, want: "2024-03-10", wantOK: true},
+		{name: "priority", file: "2024-01-15.md", content: "# 2024-02-01", sources: []string{"heading", "filename"}, want: "2024-02-01", wantOK: true},
+		{name: "no date", file: "note.md", content: "text", sources: []string{"filename", "heading"}, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := noteDate(filepath.Join(dir, tt.file), tt.content, info, tt.sources)
+			if ok != tt.wantOK {
+				t.Fatalf("noteDate() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got.Format("2006-01-02") != tt.want {
+				t.Errorf("noteDate() = %s, want %s", got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+func TestJournal_ImportFiles(t *testing.T) {
+	setup := func(t *testing.T) (*Journal, string) {
+		t.Helper()
+		j := newTestJournal(t, "2006/2006-01-02.md")
+		existing := filepath.Join(j.GetBaseDir(), "2024", "2024-01-15.md")
+		if err := os.MkdirAll(filepath.Dir(existing), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(existing, []byte("Existing\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		src := t.TempDir()
+		files := map[string]string{
+			"daily/2024-01-15.md":        "---\ntags: [x]\n---\nImported\n",
+			"notes/standup.txt":          "# Standup 2024-01-16\n\nNotes\n",
+			".obsidian/2024-01-17.md":    "config",
+			"daily/2024-01-18.md":        "\n",
+			"attachments/2024-01-19.png": "png",
+		}
+		for name, content := range files {
+			path := filepath.Join(src, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return j, src
+	}
+	read := func(t *testing.T, j *Journal, name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(j.GetBaseDir(), "2024", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		j, src := setup(t)
+		result, err := j.ImportFiles(src, FilesOptions{Conflict: config.ConflictSkip})
+		if err != nil {
+			t.Fatalf("ImportFiles() error = %v", err)
+		}
+		if len(result.Created) != 1 || len(result.Skipped) != 1 || len(result.Warnings) != 1 {
+			t.Fatalf("ImportFiles() = %+v", result)
+		}
+		if got := read(t, j, "2024-01-16.md"); got != "# Standup 2024-01-16\n\nNotes\n" {
+			t.Errorf("imported entry = %q", got)
+		}
+		if got := read(t, j, "2024-01-15.md"); got != "Existing\n" {
+			t.Errorf("existing entry = %q, want it untouched", got)
+		}
+	})
+
+	t.Run("append", func(t *testing.T) {
+		j, src := setup(t)
+		result, err := j.ImportFiles(src, FilesOptions{Conflict: config.ConflictAppend})
+		if err != nil {
+			t.Fatalf("ImportFiles() error = %v", err)
+		}
+		if len(result.Appended) != 1 {
+			t.Fatalf("ImportFiles() = %+v", result)
+		}
+		if got := read(t, j, "2024-01-15.md"); got != "Existing\n\nImported\n" {
+			t.Errorf("appended entry = %q", got)
+		}
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		j, src := setup(t)
+		if _, err := j.ImportFiles(src, FilesOptions{Conflict: config.ConflictRename}); err != nil {
+			t.Fatalf("ImportFiles() error = %v", err)
+		}
+		if got := read(t, j, "2024-01-15-2.md"); got != "---\ntags: [x]\n---\nImported\n" {
+			t.Errorf("renamed entry = %q", got)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		j, src := setup(t)
+		result, err := j.ImportFiles(src, FilesOptions{Conflict: config.ConflictRename, DryRun: true})
+		if err != nil {
+			t.Fatalf("ImportFiles() error = %v", err)
+		}
+		if len(result.Created) != 2 {
+			t.Errorf("ImportFiles() = %+v, want 2 planned files", result)
+		}
+		entries, err := j.ListEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Errorf("dry run wrote files: %v", entries)
+		}
+	})
+
+	t.Run("overlapping directory", func(t *testing.T) {
+		j, _ := setup(t)
+		if _, err := j.ImportFiles(filepath.Join(j.GetBaseDir(), "2024"), FilesOptions{}); err == nil {
+			t.Error("ImportFiles() expected error for a directory inside the journal")
+		}
+	})
+}
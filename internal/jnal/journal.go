@@ -6,16 +6,24 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
 	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/crypt"
 	"github.com/longkey1/jnal/internal/util"
 )
 
 // Journal manages journal entries
 type Journal struct {
 	cfg *config.Config
+
+	// passphrasePrompt asks for the identity file passphrase of encrypted entries
+	passphrasePrompt func() (string, error)
+	keysOnce         sync.Once
+	keys             *crypt.Keys
+	keysErr          error
 }
 
 // NewJournal creates a new Journal instance
@@ -23,9 +31,15 @@ func NewJournal(cfg *config.Config) *Journal {
 	return &Journal{cfg: cfg}
 }
 
-// GetEntryPath returns the file path for a journal entry on the given date
+// GetEntryPath returns the file path for a journal entry on the given date.
+// With encryption enabled, new entries get the .md.age extension.
 func (j *Journal) GetEntryPath(date time.Time) string {
-	relativePath := date.Format(j.cfg.Common.PathFormat)
+	return j.resolveEntryPath(j.entryPathFor(date, j.cfg.Common.PathFormat))
+}
+
+// entryPathFor returns the file path for a journal entry on the given date using the path format
+func (j *Journal) entryPathFor(date time.Time, pathFormat string) string {
+	relativePath := date.Format(pathFormat)
 	return filepath.Join(j.cfg.Common.BaseDirectory, relativePath)
 }
 
@@ -51,33 +65,19 @@ func (j *Journal) CreateEntry(date time.Time) (string, error) {
 		return entryPath, nil
 	}
 
-	// Create directory if it doesn't exist
-	dir := filepath.Dir(entryPath)
-	if err := os.MkdirAll(dir, config.DirPermission); err != nil {
-		return "", fmt.Errorf("creating directory %s: %w", dir, err)
-	}
-
-	// Create the file
-	file, err := os.OpenFile(entryPath, os.O_WRONLY|os.O_CREATE, config.FilePermission)
-	if err != nil {
-		return "", fmt.Errorf("creating file %s: %w", entryPath, err)
-	}
-	defer file.Close()
-
-	// Write template content
+	// Write template content, creating the directory if it doesn't exist
 	content, err := j.buildEntryContent(date)
 	if err != nil {
 		return "", fmt.Errorf("building entry content: %w", err)
 	}
 
-	if _, err := fmt.Fprintln(file, content); err != nil {
-		return "", fmt.Errorf("writing entry content: %w", err)
+	if err := j.writeNewEntry(entryPath, content+"\n"); err != nil {
+		return "", err
 	}
 
 	return entryPath, nil
 }
 
-
 // ListEntries returns all journal entries in the base directory
 func (j *Journal) ListEntries() (Entries, error) {
 	var entries Entries
@@ -87,27 +87,10 @@ func (j *Journal) ListEntries() (Entries, error) {
 			return err
 		}
 
-		if info.IsDir() {
-			return nil
-		}
-
-		// Only process .md files
-		if filepath.Ext(path) != ".md" {
-			return nil
+		if entry, ok := newEntry(path, info); ok {
+			entries = append(entries, entry)
 		}
 
-		// Extract date from filename
-		date, err := util.ExtractFromFilename(info.Name())
-		if err != nil {
-			// Skip files without valid date in filename
-			return nil
-		}
-
-		entries = append(entries, Entry{
-			Path: path,
-			Date: date,
-		})
-
 		return nil
 	})
 
@@ -118,6 +101,44 @@ func (j *Journal) ListEntries() (Entries, error) {
 	return entries, nil
 }
 
+// GetEntry returns the journal entry stored at the given path
+func (j *Journal) GetEntry(path string) (Entry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry, ok := newEntry(path, info)
+	if !ok {
+		return Entry{}, fmt.Errorf("not a journal entry: %s", path)
+	}
+
+	return entry, nil
+}
+
+// newEntry creates an entry from a file, returning false if the file is not a journal entry
+func newEntry(path string, info os.FileInfo) (Entry, bool) {
+	if info.IsDir() {
+		return Entry{}, false
+	}
+
+	// Only process .md and encrypted .md.age files
+	if !IsEntryFile(path) {
+		return Entry{}, false
+	}
+
+	// Extract date from filename
+	date, err := util.ExtractFromFilename(info.Name())
+	if err != nil {
+		// Skip files without valid date in filename
+		return Entry{}, false
+	}
+
+	return Entry{
+		Path: path,
+		Date: date,
+	}, true
+}
 
 // buildEntryContent builds the initial content for a new entry
 func (j *Journal) buildEntryContent(date time.Time) (string, error) {
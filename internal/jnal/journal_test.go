@@ -0,0 +1,52 @@
+package jnal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJournal_GetEntry(t *testing.T) {
+	j := newTestJournal(t, "2006/2006-01-02.md")
+	baseDir := j.GetBaseDir()
+	for _, name := range []string{"2024/2024-01-15.md", "2024/2024-01-16.md.age", "2024/notes.md", "2024/2024-01-17.txt"} {
+		path := filepath.Join(baseDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("entry\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantDate time.Time
+		wantErr  bool
+	}{
+		{"entry", "2024/2024-01-15.md", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), false},
+		{"encrypted entry", "2024/2024-01-16.md.age", time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), false},
+		{"markdown file without date", "2024/notes.md", time.Time{}, true},
+		{"other file", "2024/2024-01-17.txt", time.Time{}, true},
+		{"directory", "2024", time.Time{}, true},
+		{"missing file", "2024/2024-01-18.md", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(baseDir, filepath.FromSlash(tt.path))
+			entry, err := j.GetEntry(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetEntry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if entry.Path != path || !entry.Date.Equal(tt.wantDate) {
+				t.Errorf("GetEntry() = %+v, want path %q and date %v", entry, path, tt.wantDate)
+			}
+		})
+	}
+}
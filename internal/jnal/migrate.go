@@ -0,0 +1,273 @@
+package jnal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/git"
+	"github.com/longkey1/jnal/internal/util"
+)
+
+// Move represents a file or directory moved by a migration
+type Move struct {
+	From string
+	To   string
+}
+
+// MigrationPlan describes how entries are reorganized for a new path format
+type MigrationPlan struct {
+	// Format is the new path format
+	Format string
+	// Moves lists entries and their attachment directories, in the order they are moved
+	Moves []Move
+	// Unchanged is the number of entries already at their new path
+	Unchanged int
+	// Unmatched lists entries that don't follow the current path format; they are left in place
+	Unmatched []string
+	// Collisions describes moves whose target is taken; a plan with collisions can't be executed
+	Collisions []string
+	// Warnings describes things to check after the migration, such as relative links
+	Warnings []string
+}
+
+// relativeLinkPattern matches Markdown link and image destinations
+var relativeLinkPattern = regexp.MustCompile(`\]\(([^)\s]+)`)
+
+// formatCheckDate is used to verify that a path format round-trips dates.
+// Its day is above 12 so that swapped months and days are detected.
+var formatCheckDate = time.Date(2024, time.November, 23, 0, 0, 0, 0, time.UTC)
+
+// PlanMigration computes how entries following the current path format move to pathFormat.
+// Numbered entries (2024-01-15-2.md) keep their number, and attachment
+// directories move along with their entry.
+func (j *Journal) PlanMigration(pathFormat string) (*MigrationPlan, error) {
+	if err := validatePathFormat(pathFormat); err != nil {
+		return nil, err
+	}
+
+	entries, err := j.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+	entries.SortByDateAsc()
+
+	plan := &MigrationPlan{Format: pathFormat}
+	oldFormat := j.cfg.Common.PathFormat
+
+	// Attachment directories shared by several entries can't move with one of them
+	attachmentDirs := make(map[string][]string)
+	type entryMove struct {
+		entry Entry
+		to    string
+	}
+	var moves []entryMove
+
+	for _, e := range entries {
+		if dir, err := j.attachmentDirFor(e.Path, e.Date); err == nil {
+			attachmentDirs[dir] = append(attachmentDirs[dir], e.Path)
+		}
+
+		// Encrypted entries keep their .age extension
+		plain := strings.TrimSuffix(e.Path, EncryptedExt)
+		n, ok := entryNumber(plain, j.entryPathFor(e.Date, oldFormat))
+		if !ok {
+			plan.Unmatched = append(plan.Unmatched, e.Path)
+			continue
+		}
+		to := numberedPath(j.entryPathFor(e.Date, pathFormat), n) + e.Path[len(plain):]
+		if to == e.Path {
+			plan.Unchanged++
+			continue
+		}
+		moves = append(moves, entryMove{entry: e, to: to})
+	}
+
+	for _, m := range moves {
+		plan.Moves = append(plan.Moves, Move{From: m.entry.Path, To: m.to})
+
+		dir, err := j.attachmentDirFor(m.entry.Path, m.entry.Date)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			if len(attachmentDirs[dir]) > 1 {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s is shared by several entries and is not moved", dir))
+			} else {
+				// Keep the directory at the same place relative to the entry so that links stay valid
+				rel, err := filepath.Rel(filepath.Dir(m.entry.Path), dir)
+				if err != nil {
+					return nil, err
+				}
+				plan.Moves = append(plan.Moves, Move{From: dir, To: filepath.Join(filepath.Dir(m.to), rel)})
+			}
+		}
+
+		if filepath.Dir(m.entry.Path) != filepath.Dir(m.to) {
+			if data, err := j.ReadEntry(m.entry.Path); err == nil {
+				plan.Warnings = append(plan.Warnings, relativeLinkWarnings(m.entry.Path, data, dir)...)
+			}
+		}
+	}
+
+	plan.Collisions = findCollisions(plan.Moves)
+	return plan, nil
+}
+
+// Migrate executes the plan. Files are moved with git mv when they are tracked in a git
+// repository. If a move fails, the moves already done are rolled back.
+func (j *Journal) Migrate(plan *MigrationPlan) error {
+	if len(plan.Collisions) > 0 {
+		return fmt.Errorf("migration has %d collisions", len(plan.Collisions))
+	}
+
+	m := newMover(j.GetBaseDir())
+	var done []Move
+	for _, move := range plan.Moves {
+		if err := m.move(move.From, move.To); err != nil {
+			moveErr := fmt.Errorf("moving %s to %s: %w", move.From, move.To, err)
+			if rollbackErr := m.rollback(done); rollbackErr != nil {
+				return fmt.Errorf("%w (rollback failed: %v)", moveErr, rollbackErr)
+			}
+			return moveErr
+		}
+		done = append(done, move)
+	}
+
+	for _, move := range done {
+		removeEmptyDirs(filepath.Dir(move.From), j.GetBaseDir())
+	}
+	return nil
+}
+
+// validatePathFormat checks that entries written with the format can be found again
+func validatePathFormat(pathFormat string) error {
+	if pathFormat == "" || filepath.IsAbs(pathFormat) {
+		return fmt.Errorf("path format must be a relative path: %q", pathFormat)
+	}
+	if filepath.Ext(pathFormat) != ".md" {
+		return fmt.Errorf("path format must end with .md: %q", pathFormat)
+	}
+
+	name := filepath.Base(formatCheckDate.Format(pathFormat))
+	date, err := util.ExtractFromFilename(name)
+	if err != nil || !date.Equal(formatCheckDate) {
+		return fmt.Errorf("path format %q must contain the date as yyyy-mm-dd (2006-01-02) in the file name", pathFormat)
+	}
+	return nil
+}
+
+// entryNumber returns 1 if path is the expected entry path, or n if it is the
+// n-th numbered entry of the day (2024-01-15-2.md)
+func entryNumber(path, expected string) (int, bool) {
+	if path == expected {
+		return 1, true
+	}
+	ext := filepath.Ext(expected)
+	prefix := strings.TrimSuffix(expected, ext) + "-"
+	if !strings.HasPrefix(path, prefix) || filepath.Ext(path) != ext {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, prefix), ext))
+	if err != nil || n < 2 {
+		return 0, false
+	}
+	return n, true
+}
+
+// findCollisions returns the moves whose target exists, is another move's source,
+// or is the target of several moves
+func findCollisions(moves []Move) []string {
+	sources := make(map[string]bool)
+	for _, m := range moves {
+		sources[m.From] = true
+	}
+
+	var collisions []string
+	targets := make(map[string]string)
+	for _, m := range moves {
+		switch {
+		case targets[m.To] != "":
+			collisions = append(collisions, fmt.Sprintf("%s and %s both move to %s", targets[m.To], m.From, m.To))
+		case sources[m.To]:
+			collisions = append(collisions, fmt.Sprintf("%s moves to %s, which is moved itself", m.From, m.To))
+		default:
+			if _, err := os.Lstat(m.To); err == nil {
+				collisions = append(collisions, fmt.Sprintf("%s moves to %s, which already exists", m.From, m.To))
+			}
+		}
+		targets[m.To] = m.From
+	}
+	return collisions
+}
+
+// relativeLinkWarnings returns warnings for relative links of the entry outside its
+// attachments directory, which break when the entry moves to another directory
+func relativeLinkWarnings(entryPath string, data []byte, attachmentDir string) []string {
+	attachmentRel, _ := filepath.Rel(filepath.Dir(entryPath), attachmentDir)
+	attachmentRel = filepath.ToSlash(attachmentRel) + "/"
+
+	var warnings []string
+	seen := make(map[string]bool)
+	for _, m := range relativeLinkPattern.FindAllSubmatch(data, -1) {
+		dest := string(m[1])
+		if seen[dest] || strings.Contains(dest, ":") || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "/") ||
+			strings.HasPrefix(dest, attachmentRel) {
+			continue
+		}
+		seen[dest] = true
+		warnings = append(warnings, fmt.Sprintf("%s links to %s, which may break", entryPath, dest))
+	}
+	return warnings
+}
+
+// removeEmptyDirs removes dir and its parents up to (excluding) baseDir while they are empty
+func removeEmptyDirs(dir, baseDir string) {
+	for dir != baseDir && isWithin(dir, baseDir) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// mover moves files and directories, using git mv for files tracked in a git repository
+type mover struct {
+	repo *git.Repo
+}
+
+// newMover creates a mover for the base directory
+func newMover(baseDir string) *mover {
+	repo, err := git.Open(baseDir)
+	if err != nil {
+		repo = nil
+	}
+	return &mover{repo: repo}
+}
+
+// move moves from to to, creating the parent directory of to
+func (m *mover) move(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), config.DirPermission); err != nil {
+		return err
+	}
+	if m.repo != nil && m.repo.Tracked(from) {
+		return m.repo.Move(from, to)
+	}
+	return os.Rename(from, to)
+}
+
+// rollback reverts the moves in reverse order
+func (m *mover) rollback(done []Move) error {
+	for i := len(done) - 1; i >= 0; i-- {
+		if err := m.move(done[i].To, done[i].From); err != nil {
+			return fmt.Errorf("moving %s back: %w", done[i].To, err)
+		}
+		removeEmptyDirs(filepath.Dir(done[i].To), filepath.Dir(done[i].From))
+	}
+	return nil
+}
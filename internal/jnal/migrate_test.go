@@ -0,0 +1,197 @@
+package jnal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidatePathFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		wantErr bool
+	}{
+		{name: "flat", format: "2006-01-02.md"},
+		{name: "nested", format: "2006/01/2006-01-02.md"},
+		{name: "absolute", format: "/j/2006-01-02.md", wantErr: true},
+		{name: "not markdown", format: "2006-01-02.txt", wantErr: true},
+		{name: "date in directory only", format: "2006-01-02/entry.md", wantErr: true},
+		{name: "swapped month and day", format: "2006-02-01.md", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePathFormat(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePathFormat(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEntryNumber(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   int
+		wantOK bool
+	}{
+		{path: "/j/2024-01-15.md", want: 1, wantOK: true},
+		{path: "/j/2024-01-15-2.md", want: 2, wantOK: true},
+		{path: "/j/2024-01-15-1.md"},
+		{path: "/j/2024-01-15-notes.md"},
+		{path: "/j/2024/2024-01-15.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := entryNumber(tt.path, "/j/2024-01-15.md")
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("entryNumber(%q) = %d, %v, want %d, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestJournal_Migrate(t *testing.T) {
+	j := newTestJournal(t, "2006-01-02.md")
+	base := j.GetBaseDir()
+
+	writeTestFile(t, filepath.Join(base, "2024-01-15.md"), "![a](attachments/2024-01-15/a.png)\n")
+	writeTestFile(t, filepath.Join(base, "2024-01-15-2.md"), "second\n")
+	writeTestFile(t, filepath.Join(base, "attachments", "2024-01-15", "a.png"), "image")
+	writeTestFile(t, filepath.Join(base, "2024-02-01.md"), "[notes](../notes.md)\n")
+
+	plan, err := j.PlanMigration("2006/01/2006-01-02.md")
+	if err != nil {
+		t.Fatalf("PlanMigration() error = %v", err)
+	}
+	if len(plan.Collisions) > 0 {
+		t.Fatalf("Collisions = %v", plan.Collisions)
+	}
+	if len(plan.Moves) != 4 {
+		t.Fatalf("len(Moves) = %d, want 4: %v", len(plan.Moves), plan.Moves)
+	}
+	if len(plan.Warnings) != 1 || !strings.Contains(plan.Warnings[0], "../notes.md") {
+		t.Errorf("Warnings = %v, want a warning about ../notes.md", plan.Warnings)
+	}
+
+	if err := j.Migrate(plan); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	for _, path := range []string{
+		"2024/01/2024-01-15.md",
+		"2024/01/2024-01-15-2.md",
+		"2024/01/attachments/2024-01-15/a.png",
+		"2024/02/2024-02-01.md",
+	} {
+		if _, err := os.Stat(filepath.Join(base, path)); err != nil {
+			t.Errorf("%s not found: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(base, "attachments")); !os.IsNotExist(err) {
+		t.Errorf("old attachments directory not removed: %v", err)
+	}
+}
+
+func TestJournal_PlanMigration_Collision(t *testing.T) {
+	j := newTestJournal(t, "2006-01-02.md")
+	base := j.GetBaseDir()
+
+	writeTestFile(t, filepath.Join(base, "2024-01-15.md"), "old\n")
+	writeTestFile(t, filepath.Join(base, "2024", "2024-01-15.md"), "taken\n")
+
+	plan, err := j.PlanMigration("2006/2006-01-02.md")
+	if err != nil {
+		t.Fatalf("PlanMigration() error = %v", err)
+	}
+	if len(plan.Collisions) != 1 {
+		t.Fatalf("Collisions = %v, want 1", plan.Collisions)
+	}
+	if err := j.Migrate(plan); err == nil {
+		t.Error("Migrate() with collisions should fail")
+	}
+	if _, err := os.Stat(filepath.Join(base, "2024-01-15.md")); err != nil {
+		t.Errorf("entry moved despite collision: %v", err)
+	}
+}
+
+func TestJournal_Migrate_Rollback(t *testing.T) {
+	j := newTestJournal(t, "2006-01-02.md")
+	base := j.GetBaseDir()
+
+	writeTestFile(t, filepath.Join(base, "2024-01-15.md"), "first\n")
+	writeTestFile(t, filepath.Join(base, "2024-01-16.md"), "second\n")
+
+	plan, err := j.PlanMigration("2006/2006-01-02.md")
+	if err != nil {
+		t.Fatalf("PlanMigration() error = %v", err)
+	}
+
+	// Make the second move fail after the plan was made
+	if err := os.Remove(filepath.Join(base, "2024-01-16.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := j.Migrate(plan); err == nil {
+		t.Fatal("Migrate() should fail")
+	}
+	if _, err := os.Stat(filepath.Join(base, "2024-01-15.md")); err != nil {
+		t.Errorf("first move not rolled back: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "2024")); !os.IsNotExist(err) {
+		t.Errorf("new directory not removed: %v", err)
+	}
+}
+
+func TestJournal_Migrate_Git(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	j := newTestJournal(t, "2006-01-02.md")
+	base := j.GetBaseDir()
+	writeTestFile(t, filepath.Join(base, "2024-01-15.md"), "entry\n")
+
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", base}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+		return string(out)
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	plan, err := j.PlanMigration("2006/2006-01-02.md")
+	if err != nil {
+		t.Fatalf("PlanMigration() error = %v", err)
+	}
+	if err := j.Migrate(plan); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	status := git("status", "--porcelain")
+	if !strings.Contains(status, "R  2024-01-15.md -> 2024/2024-01-15.md") {
+		t.Errorf("git status = %q, want a staged rename", status)
+	}
+}
@@ -0,0 +1,121 @@
+package jnal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// OnThisDayOptions represents options for finding past entries
+type OnThisDayOptions struct {
+	// WeekAgo includes the entry from the same weekday a week ago
+	WeekAgo bool
+	// MonthAgo includes the entry from the same weekday four weeks ago
+	MonthAgo bool
+	// YearAgo includes the entry from the same ISO week and weekday a year ago
+	YearAgo bool
+}
+
+// Memory represents a past entry related to a date
+type Memory struct {
+	Entry Entry
+	// Label describes how the entry relates to the date (e.g. "2 years ago")
+	Label string
+}
+
+// OnThisDay returns past entries related to the date, see FindOnThisDay
+func (j *Journal) OnThisDay(date time.Time, opts OnThisDayOptions) ([]Memory, error) {
+	entries, err := j.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+	return FindOnThisDay(entries, date, opts), nil
+}
+
+// FindOnThisDay returns the entries written on the same month and day in previous years,
+// most recent first, followed by the same-weekday entries requested in opts
+func FindOnThisDay(entries Entries, date time.Time, opts OnThisDayOptions) []Memory {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	sorted := make(Entries, len(entries))
+	copy(sorted, entries)
+	sorted.SortByDateDesc()
+
+	var memories []Memory
+	for _, e := range sorted {
+		if e.Date.Year() < date.Year() && e.Date.Month() == date.Month() && e.Date.Day() == date.Day() {
+			memories = append(memories, Memory{Entry: e, Label: yearsAgoLabel(date.Year() - e.Date.Year())})
+		}
+	}
+
+	sameWeekday := []struct {
+		enabled bool
+		date    time.Time
+		label   string
+	}{
+		{opts.WeekAgo, date.AddDate(0, 0, -7), "a week ago"},
+		{opts.MonthAgo, date.AddDate(0, 0, -28), "four weeks ago"},
+		{opts.YearAgo, isoYearAgo(date), "same weekday a year ago"},
+	}
+	for _, sw := range sameWeekday {
+		if !sw.enabled {
+			continue
+		}
+		for _, e := range sorted {
+			if e.Date.Equal(sw.date) {
+				memories = append(memories, Memory{Entry: e, Label: sw.label})
+			}
+		}
+	}
+
+	return memories
+}
+
+// Excerpt returns the beginning of the Markdown content as plain text,
+// skipping front matter, headings and blank lines, limited to maxRunes characters
+func Excerpt(content string, maxRunes int) string {
+	var parts []string
+	for _, line := range strings.Split(StripFrontMatter(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts = append(parts, line)
+		if utf8.RuneCountInString(strings.Join(parts, " ")) >= maxRunes {
+			break
+		}
+	}
+
+	text := strings.Join(parts, " ")
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:maxRunes])) + "…"
+}
+
+// isoYearAgo returns the date with the same ISO week and weekday in the previous ISO year
+func isoYearAgo(date time.Time) time.Time {
+	year, week := date.ISOWeek()
+	weekday := (int(date.Weekday()) + 6) % 7 // Monday = 0
+
+	// January 4th is always in ISO week 1
+	jan4 := time.Date(year-1, time.January, 4, 0, 0, 0, 0, time.UTC)
+	week1Monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	result := week1Monday.AddDate(0, 0, (week-1)*7+weekday)
+
+	// Week 53 doesn't exist in every year; fall back to 52 weeks earlier
+	if y, _ := result.ISOWeek(); y != year-1 {
+		return date.AddDate(0, 0, -364)
+	}
+	return result
+}
+
+// yearsAgoLabel returns a label such as "1 year ago"
+func yearsAgoLabel(years int) string {
+	if years == 1 {
+		return "1 year ago"
+	}
+	return fmt.Sprintf("%d years ago", years)
+}
@@ -0,0 +1,69 @@
+package jnal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindOnThisDay(t *testing.T) {
+	d := func(y int, m time.Month, day int) time.Time {
+		return time.Date(y, m, day, 0, 0, 0, 0, time.UTC)
+	}
+	entries := Entries{
+		{Path: "2021-03-15.md", Date: d(2021, 3, 15)},
+		{Path: "2023-03-15.md", Date: d(2023, 3, 15)},
+		{Path: "2024-03-15.md", Date: d(2024, 3, 15)},
+		{Path: "2024-03-08.md", Date: d(2024, 3, 8)},
+		{Path: "2024-02-16.md", Date: d(2024, 2, 16)},
+		{Path: "2023-03-17.md", Date: d(2023, 3, 17)},
+	}
+	date := d(2024, 3, 15) // Friday, ISO week 11
+
+	memories := FindOnThisDay(entries, date, OnThisDayOptions{})
+	if len(memories) != 2 {
+		t.Fatalf("len(memories) = %d, want 2", len(memories))
+	}
+	if memories[0].Entry.Path != "2023-03-15.md" || memories[0].Label != "1 year ago" {
+		t.Errorf("memories[0] = %+v", memories[0])
+	}
+	if memories[1].Entry.Path != "2021-03-15.md" || memories[1].Label != "3 years ago" {
+		t.Errorf("memories[1] = %+v", memories[1])
+	}
+
+	memories = FindOnThisDay(entries, date, OnThisDayOptions{WeekAgo: true, MonthAgo: true, YearAgo: true})
+	var labels []string
+	for _, m := range memories[2:] {
+		labels = append(labels, m.Entry.Path+" "+m.Label)
+	}
+	want := []string{
+		"2024-03-08.md a week ago",
+		"2024-02-16.md four weeks ago",
+		"2023-03-17.md same weekday a year ago",
+	}
+	if len(labels) != len(want) {
+		t.Fatalf("same weekday memories = %v, want %v", labels, want)
+	}
+	for i := range want {
+		if labels[i] != want[i] {
+			t.Errorf("memory %d = %q, want %q", i, labels[i], want[i])
+		}
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		content string
+		max     int
+		want    string
+	}{
+		{content: "# 2024-01-15\n\nFirst line.\nSecond line.\n", max: 100, want: "First line. Second line."},
+		{content: "# Title\n\nabcdefghij", max: 5, want: "abcde…"},
+		{content: "# Only a heading\n", max: 10, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := Excerpt(tt.content, tt.max); got != tt.want {
+			t.Errorf("Excerpt(%q, %d) = %q, want %q", tt.content, tt.max, got, tt.want)
+		}
+	}
+}
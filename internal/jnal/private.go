@@ -0,0 +1,95 @@
+package jnal
+
+import "strings"
+
+// Markers of private blocks. Each marker must be on a line of its own.
+const (
+	privateFenceOpen    = ":::private"
+	privateFenceClose   = ":::"
+	privateCommentOpen  = "<!-- private -->"
+	privateCommentClose = "<!-- /private -->"
+)
+
+// Segment is a part of an entry body that is either public or private
+type Segment struct {
+	Text    string
+	Private bool
+}
+
+// IsPrivateEntry reports whether the entry content is marked private in its front matter.
+// Any private value other than false counts, so that a typo such as private: yes never
+// publishes an entry.
+func IsPrivateEntry(content string) bool {
+	fm, _, _ := splitFrontMatter(content)
+	value, ok := fm["private"]
+	if !ok {
+		return false
+	}
+	b, isBool := value.(bool)
+	return !isBool || b
+}
+
+// SplitPrivate splits the body of an entry into public and private segments.
+// Private blocks are fenced by ":::private" and ":::" lines or by "<!-- private -->" and
+// "<!-- /private -->" lines. Markers inside code blocks are ignored, and an unterminated
+// private block extends to the end of the body.
+func SplitPrivate(body string) []Segment {
+	var segments []Segment
+	var current strings.Builder
+	private := false
+	closer := ""
+	codeFence := ""
+
+	flush := func() {
+		if current.Len() > 0 {
+			segments = append(segments, Segment{Text: current.String(), Private: private})
+			current.Reset()
+		}
+	}
+
+	for _, line := range strings.SplitAfter(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if codeFence != "" {
+			if closesFence(line, codeFence) {
+				codeFence = ""
+			}
+			current.WriteString(line)
+			continue
+		}
+		if fence := openingFence(line); fence != "" {
+			codeFence = fence
+			current.WriteString(line)
+			continue
+		}
+
+		switch {
+		case !private && (trimmed == privateFenceOpen || trimmed == privateCommentOpen):
+			flush()
+			private = true
+			closer = privateFenceClose
+			if trimmed == privateCommentOpen {
+				closer = privateCommentClose
+			}
+		case private && trimmed == closer:
+			flush()
+			private = false
+		default:
+			current.WriteString(line)
+		}
+	}
+	flush()
+
+	return segments
+}
+
+// RedactPrivate returns the body without its private blocks
+func RedactPrivate(body string) string {
+	var sb strings.Builder
+	for _, s := range SplitPrivate(body) {
+		if !s.Private {
+			sb.WriteString(s.Text)
+		}
+	}
+	return sb.String()
+}
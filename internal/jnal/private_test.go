@@ -0,0 +1,104 @@
+package jnal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsPrivateEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{name: "no front matter", content: "private: true\n", want: false},
+		{name: "private", content: "---\nprivate: true\n---\nBody\n", want: true},
+		{name: "not private", content: "---\nprivate: false\n---\nBody\n", want: false},
+		{name: "other keys", content: "---\ntags: [work]\n---\nBody\n", want: false},
+		{name: "non bool value", content: "---\nprivate: yes\n---\nBody\n", want: true},
+		{name: "invalid yaml is not front matter", content: "---\nprivate: [true\n---\nBody\n", want: false},
+		{name: "thematic break first", content: "---\nPrivate thoughts\n\n---\nBody\n", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPrivateEntry(tt.content); got != tt.want {
+				t.Errorf("IsPrivateEntry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitPrivate(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Segment
+	}{
+		{
+			name: "no private blocks",
+			body: "Public\n",
+			want: []Segment{{Text: "Public\n"}},
+		},
+		{
+			name: "fenced block",
+			body: "Before\n:::private\nSecret\n:::\nAfter\n",
+			want: []Segment{{Text: "Before\n"}, {Text: "Secret\n", Private: true}, {Text: "After\n"}},
+		},
+		{
+			name: "comment pair",
+			body: "Before\n<!-- private -->\nSecret\n<!-- /private -->\nAfter\n",
+			want: []Segment{{Text: "Before\n"}, {Text: "Secret\n", Private: true}, {Text: "After\n"}},
+		},
+		{
+			name: "unterminated",
+			body: "Before\n:::private\nSecret\n",
+			want: []Segment{{Text: "Before\n"}, {Text: "Secret\n", Private: true}},
+		},
+		{
+			name: "marker in code block",
+			body: "```\n:::private\n```\n",
+			want: []Segment{{Text: "```\n:::private\n```\n"}},
+		},
+		{
+			name: "code block in private block",
+			body: ":::private\n~~~\n:::\n~~~\n:::\nAfter\n",
+			want: []Segment{{Text: "~~~\n:::\n~~~\n", Private: true}, {Text: "After\n"}},
+		},
+		{
+			name: "inline code at the start of a line",
+			body: "```go``` fences\n:::private\nSecret\n:::\n",
+			want: []Segment{{Text: "```go``` fences\n"}, {Text: "Secret\n", Private: true}},
+		},
+		{
+			name: "indented code",
+			body: "    ```\n:::private\nSecret\n:::\n",
+			want: []Segment{{Text: "    ```\n"}, {Text: "Secret\n", Private: true}},
+		},
+		{
+			name: "shorter closing fence",
+			body: "````\n```\n:::private\n````\n:::private\nSecret\n:::\n",
+			want: []Segment{{Text: "````\n```\n:::private\n````\n"}, {Text: "Secret\n", Private: true}},
+		},
+		{
+			name: "closing fence of the other character",
+			body: "```\n~~~\n:::private\n```\n:::private\nSecret\n:::\n",
+			want: []Segment{{Text: "```\n~~~\n:::private\n```\n"}, {Text: "Secret\n", Private: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitPrivate(tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitPrivate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactPrivate(t *testing.T) {
+	body := "Before\n:::private\nSecret\n:::\nMiddle\n<!-- private -->\nHidden\n<!-- /private -->\nAfter\n"
+	if got, want := RedactPrivate(body), "Before\nMiddle\nAfter\n"; got != want {
+		t.Errorf("RedactPrivate() = %q, want %q", got, want)
+	}
+}
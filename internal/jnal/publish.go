@@ -0,0 +1,58 @@
+package jnal
+
+import (
+	"time"
+
+	"github.com/longkey1/jnal/internal/util"
+)
+
+// PublishStatus is the publishing metadata of an entry from its front matter
+type PublishStatus struct {
+	// Draft is set by draft: true, and for entries whose publish_date can't be parsed
+	Draft bool
+	// PublishDate is the publish_date, or the zero time when unset
+	PublishDate time.Time
+}
+
+// ParsePublishStatus returns the publishing metadata in the front matter of the entry content.
+// Any draft value other than false counts as a draft, as does a publish_date that fails
+// to parse, so that a typo never publishes an entry early.
+func ParsePublishStatus(content string) PublishStatus {
+	fm, _, _ := splitFrontMatter(content)
+
+	var status PublishStatus
+	if value, ok := fm["draft"]; ok {
+		b, isBool := value.(bool)
+		status.Draft = !isBool || b
+	}
+	if value, ok := fm["publish_date"]; ok {
+		date, ok := parsePublishDate(value)
+		if !ok {
+			status.Draft = true
+		}
+		status.PublishDate = date
+	}
+	return status
+}
+
+// IsFuture reports whether the publish_date is after today. Entries without
+// a publish_date are never in the future, whatever their date.
+func (s PublishStatus) IsFuture(today time.Time) bool {
+	return !s.PublishDate.IsZero() && util.Format(s.PublishDate) > util.Format(today)
+}
+
+// parsePublishDate parses a publish_date value, a yyyy-mm-dd date or a YAML timestamp
+func parsePublishDate(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC), true
+	case string:
+		if t, err := util.Parse(v); err == nil {
+			return t, true
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,64 @@
+package jnal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePublishStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    PublishStatus
+	}{
+		{name: "no front matter", content: "Body\n"},
+		{name: "draft", content: "---\ndraft: true\n---\nBody\n", want: PublishStatus{Draft: true}},
+		{name: "not draft", content: "---\ndraft: false\n---\nBody\n"},
+		{name: "non bool draft", content: "---\ndraft: yes\n---\nBody\n", want: PublishStatus{Draft: true}},
+		{
+			name:    "publish date",
+			content: "---\npublish_date: 2024-01-21\n---\nBody\n",
+			want:    PublishStatus{PublishDate: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "publish timestamp",
+			content: "---\npublish_date: 2024-01-21T09:00:00+09:00\n---\nBody\n",
+			want:    PublishStatus{PublishDate: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		},
+		{name: "invalid publish date", content: "---\npublish_date: next week\n---\nBody\n", want: PublishStatus{Draft: true}},
+		{name: "invalid yaml is not front matter", content: "---\ndraft: [true\n---\nBody\n"},
+		{name: "thematic break first", content: "---\nMorning notes\n\n---\nEvening notes\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParsePublishStatus(tt.content); got != tt.want {
+				t.Errorf("ParsePublishStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishStatus_IsFuture(t *testing.T) {
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	date := func(day int) time.Time { return time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		status PublishStatus
+		want   bool
+	}{
+		{name: "no publish date", want: false},
+		{name: "publish date today", status: PublishStatus{PublishDate: date(15)}, want: false},
+		{name: "past publish date", status: PublishStatus{PublishDate: date(14)}, want: false},
+		{name: "future publish date", status: PublishStatus{PublishDate: date(21)}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.IsFuture(today); got != tt.want {
+				t.Errorf("IsFuture() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
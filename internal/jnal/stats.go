@@ -0,0 +1,283 @@
+package jnal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/longkey1/jnal/internal/util"
+)
+
+// Stats represents journaling statistics
+type Stats struct {
+	TotalEntries   int          `json:"total_entries"`
+	TotalWords     int          `json:"total_words"`
+	WordsPerEntry  float64      `json:"words_per_entry"`
+	FirstDate      string       `json:"first_date,omitempty"`
+	LastDate       string       `json:"last_date,omitempty"`
+	LongestStreak  Streak       `json:"longest_streak"`
+	CurrentStreak  Streak       `json:"current_streak"`
+	MissedDays     int          `json:"missed_days"`
+	BusiestWeekday string       `json:"busiest_weekday,omitempty"`
+	Weekdays       []DayCount   `json:"weekdays"`
+	Months         []PeriodStat `json:"months"`
+	Years          []PeriodStat `json:"years"`
+	// Days maps dates (yyyy-mm-dd) with entries to their word counts
+	Days map[string]int `json:"days"`
+}
+
+// Streak represents a run of consecutive days with entries
+type Streak struct {
+	Days  int    `json:"days"`
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// DayCount represents the number of entries written on a weekday
+type DayCount struct {
+	Weekday string `json:"weekday"`
+	Entries int    `json:"entries"`
+}
+
+// PeriodStat represents statistics for a month or a year
+type PeriodStat struct {
+	Period        string  `json:"period"`
+	Entries       int     `json:"entries"`
+	Words         int     `json:"words"`
+	WordsPerEntry float64 `json:"words_per_entry"`
+	// Change is the change in entries from the previous period in percent (years only)
+	Change *float64 `json:"change,omitempty"`
+}
+
+// Stats computes journaling statistics for all entries up to today
+func (j *Journal) Stats(today time.Time) (*Stats, error) {
+	entries, err := j.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		data, err := j.ReadEntry(entries[i].Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading entry %s: %w", entries[i].Path, err)
+		}
+		entries[i].Content = string(data)
+	}
+
+	return ComputeStats(entries, today), nil
+}
+
+// ComputeStats computes statistics from entries whose Content holds the raw Markdown
+func ComputeStats(entries Entries, today time.Time) *Stats {
+	stats := &Stats{
+		Days: make(map[string]int),
+	}
+
+	sorted := make(Entries, len(entries))
+	copy(sorted, entries)
+	sorted.SortByDateAsc()
+
+	weekdays := make([]int, 7)
+	months := make(map[string]*PeriodStat)
+	years := make(map[string]*PeriodStat)
+
+	for _, e := range sorted {
+		words := CountWords(StripFrontMatter(e.Content))
+		day := util.Format(e.Date)
+
+		stats.TotalEntries++
+		stats.TotalWords += words
+		stats.Days[day] += words
+		weekdays[e.Date.Weekday()]++
+
+		addPeriod(months, e.Date.Format("2006-01"), words)
+		addPeriod(years, e.Date.Format("2006"), words)
+	}
+
+	if stats.TotalEntries == 0 {
+		stats.Weekdays = weekdayCounts(weekdays)
+		return stats
+	}
+
+	stats.WordsPerEntry = average(stats.TotalWords, stats.TotalEntries)
+	stats.FirstDate = util.Format(sorted[0].Date)
+	stats.LastDate = util.Format(sorted[len(sorted)-1].Date)
+	stats.Weekdays = weekdayCounts(weekdays)
+	stats.BusiestWeekday = busiestWeekday(weekdays)
+	stats.Months = sortedPeriods(fillMonths(months, sorted[0].Date, sorted[len(sorted)-1].Date))
+	stats.Years = sortedPeriods(years)
+
+	for i := 1; i < len(stats.Years); i++ {
+		prev := stats.Years[i-1].Entries
+		change := float64(stats.Years[i].Entries-prev) / float64(prev) * 100
+		stats.Years[i].Change = &change
+	}
+
+	stats.LongestStreak, stats.CurrentStreak = streaks(stats.Days, today)
+	stats.MissedDays = missedDays(stats.Days, sorted[0].Date, today)
+
+	return stats
+}
+
+// CountWords counts the words in the text.
+// Each CJK character is counted as a word since those scripts don't separate words with spaces.
+func CountWords(text string) int {
+	count := 0
+	for _, field := range strings.Fields(text) {
+		inWord := false
+		for _, r := range field {
+			if isCJK(r) {
+				count++
+				inWord = false
+				continue
+			}
+			if !inWord {
+				count++
+				inWord = true
+			}
+		}
+	}
+	return count
+}
+
+// isCJK reports whether r is a Chinese, Japanese or Korean character
+func isCJK(r rune) bool {
+	switch {
+	case r >= 0x3040 && r <= 0x30ff: // Hiragana, Katakana
+		return true
+	case r >= 0x3400 && r <= 0x9fff: // CJK Unified Ideographs
+		return true
+	case r >= 0xac00 && r <= 0xd7af: // Hangul Syllables
+		return true
+	case r >= 0xf900 && r <= 0xfaff: // CJK Compatibility Ideographs
+		return true
+	}
+	return false
+}
+
+// addPeriod adds an entry with the given number of words to the period
+func addPeriod(periods map[string]*PeriodStat, period string, words int) {
+	p, ok := periods[period]
+	if !ok {
+		p = &PeriodStat{Period: period}
+		periods[period] = p
+	}
+	p.Entries++
+	p.Words += words
+}
+
+// fillMonths adds empty months between first and last so that gaps show up in the trend
+func fillMonths(months map[string]*PeriodStat, first, last time.Time) map[string]*PeriodStat {
+	end := time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for m := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(end); m = m.AddDate(0, 1, 0) {
+		period := m.Format("2006-01")
+		if _, ok := months[period]; !ok {
+			months[period] = &PeriodStat{Period: period}
+		}
+	}
+	return months
+}
+
+// sortedPeriods returns the periods in chronological order
+func sortedPeriods(periods map[string]*PeriodStat) []PeriodStat {
+	result := make([]PeriodStat, 0, len(periods))
+	for _, p := range periods {
+		p.WordsPerEntry = average(p.Words, p.Entries)
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Period < result[j].Period
+	})
+	return result
+}
+
+// weekdayCounts returns entry counts per weekday, starting with Monday
+func weekdayCounts(weekdays []int) []DayCount {
+	counts := make([]DayCount, 0, 7)
+	for i := 1; i <= 7; i++ {
+		wd := time.Weekday(i % 7)
+		counts = append(counts, DayCount{Weekday: wd.String(), Entries: weekdays[wd]})
+	}
+	return counts
+}
+
+// busiestWeekday returns the weekday with the most entries
+func busiestWeekday(weekdays []int) string {
+	busiest := time.Monday
+	for i := 1; i <= 7; i++ {
+		wd := time.Weekday(i % 7)
+		if weekdays[wd] > weekdays[busiest] {
+			busiest = wd
+		}
+	}
+	return busiest.String()
+}
+
+// streaks returns the longest streak and the current streak of consecutive days.
+// The current streak is still alive if the last entry was written yesterday.
+// Entries dated after today, written ahead of time, don't count.
+func streaks(days map[string]int, today time.Time) (Streak, Streak) {
+	dates := make([]string, 0, len(days))
+	for d := range days {
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+
+	todayDate := dateOnly(today)
+	var longest, run Streak
+	var prev time.Time
+	for _, d := range dates {
+		date, err := util.Parse(d)
+		if err != nil {
+			continue
+		}
+		if date.After(todayDate) {
+			break
+		}
+		if run.Days > 0 && date.Equal(prev.AddDate(0, 0, 1)) {
+			run.Days++
+			run.End = d
+		} else {
+			run = Streak{Days: 1, Start: d, End: d}
+		}
+		if run.Days > longest.Days {
+			longest = run
+		}
+		prev = date
+	}
+
+	if run.Days > 0 && !prev.Before(todayDate.AddDate(0, 0, -1)) {
+		return longest, run
+	}
+	return longest, Streak{}
+}
+
+// missedDays returns the number of days without entries from the first entry until today.
+// Today only counts as missed once it is over.
+func missedDays(days map[string]int, first, today time.Time) int {
+	missed := 0
+	end := dateOnly(today).AddDate(0, 0, -1)
+	if _, ok := days[util.Format(today)]; ok {
+		end = dateOnly(today)
+	}
+	for d := dateOnly(first); !d.After(end); d = d.AddDate(0, 0, 1) {
+		if _, ok := days[util.Format(d)]; !ok {
+			missed++
+		}
+	}
+	return missed
+}
+
+// dateOnly returns the date in UTC, matching dates parsed from entry file names
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// average returns total/count, or 0 if count is 0
+func average(total, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return float64(total) / float64(count)
+}
@@ -0,0 +1,100 @@
+package jnal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/longkey1/jnal/internal/util"
+)
+
+func TestComputeStats(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	entries := Entries{
+		{Path: "2023-12-30.md", Date: date("2023-12-30"), Content: "one two"},
+		{Path: "2024-01-01.md", Date: date("2024-01-01"), Content: "one two three"},
+		{Path: "2024-01-02.md", Date: date("2024-01-02"), Content: "one"},
+		{Path: "2024-01-03.md", Date: date("2024-01-03"), Content: "one two three four"},
+		{Path: "2024-03-09.md", Date: date("2024-03-09"), Content: "one"},
+		{Path: "2024-03-10.md", Date: date("2024-03-10"), Content: "one"},
+	}
+	today := time.Date(2024, 3, 11, 9, 0, 0, 0, time.Local)
+
+	stats := ComputeStats(entries, today)
+
+	if stats.TotalEntries != 6 {
+		t.Errorf("TotalEntries = %d, want 6", stats.TotalEntries)
+	}
+	if stats.TotalWords != 12 {
+		t.Errorf("TotalWords = %d, want 12", stats.TotalWords)
+	}
+	if stats.LongestStreak != (Streak{Days: 3, Start: "2024-01-01", End: "2024-01-03"}) {
+		t.Errorf("LongestStreak = %+v", stats.LongestStreak)
+	}
+	if stats.CurrentStreak != (Streak{Days: 2, Start: "2024-03-09", End: "2024-03-10"}) {
+		t.Errorf("CurrentStreak = %+v", stats.CurrentStreak)
+	}
+	// 2023-12-30 to 2024-03-10 is 72 days with 6 entries (today is not over yet)
+	if stats.MissedDays != 66 {
+		t.Errorf("MissedDays = %d, want 66", stats.MissedDays)
+	}
+	// 2023-12 to 2024-03 including the empty month of February
+	if len(stats.Months) != 4 || stats.Months[2].Period != "2024-02" || stats.Months[2].Entries != 0 {
+		t.Errorf("Months = %+v", stats.Months)
+	}
+	if len(stats.Years) != 2 || stats.Years[1].Change == nil || *stats.Years[1].Change != 400 {
+		t.Errorf("Years = %+v", stats.Years)
+	}
+}
+
+func TestComputeStats_BrokenStreak(t *testing.T) {
+	entries := Entries{
+		{Path: "2024-03-01.md", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	stats := ComputeStats(entries, time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC))
+	if stats.CurrentStreak.Days != 0 {
+		t.Errorf("CurrentStreak = %+v, want none", stats.CurrentStreak)
+	}
+}
+
+func TestComputeStats_FutureEntries(t *testing.T) {
+	date := func(day int) time.Time { return time.Date(2024, 3, day, 0, 0, 0, 0, time.UTC) }
+	var entries Entries
+	for _, day := range []int{2, 3, 5, 6, 7, 8} {
+		entries = append(entries, Entry{Path: util.Format(date(day)) + ".md", Date: date(day)})
+	}
+
+	stats := ComputeStats(entries, date(5))
+	want := Streak{Days: 2, Start: "2024-03-02", End: "2024-03-03"}
+	if stats.LongestStreak != want {
+		t.Errorf("LongestStreak = %+v, want %+v", stats.LongestStreak, want)
+	}
+	want = Streak{Days: 1, Start: "2024-03-05", End: "2024-03-05"}
+	if stats.CurrentStreak != want {
+		t.Errorf("CurrentStreak = %+v, want %+v", stats.CurrentStreak, want)
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "", want: 0},
+		{text: "# Hello world\n\n- one two", want: 6},
+		{text: "今日は晴れ", want: 5},
+		{text: "Go言語 rocks", want: 4},
+	}
+
+	for _, tt := range tests {
+		if got := CountWords(tt.text); got != tt.want {
+			t.Errorf("CountWords(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/crypt"
+	"github.com/yuin/goldmark/ast"
+)
+
+// isAssetPath reports whether a slash-separated path relative to the base
+// directory may be exposed as an asset. Markdown files, encrypted files,
+// dotfiles and paths escaping the base directory are rejected.
+func isAssetPath(rel string) bool {
+	if rel == "" || rel == "." {
+		return false
+	}
+	for _, seg := range strings.Split(rel, "/") {
+		if seg == "" || seg == ".." || strings.HasPrefix(seg, ".") {
+			return false
+		}
+	}
+	ext := path.Ext(rel)
+	return !strings.EqualFold(ext, ".md") && !strings.EqualFold(ext, crypt.Ext)
+}
+
+// entryRelDir returns the directory of an entry relative to the base directory
+// in slash-separated form ("." for entries at the top level)
+func entryRelDir(baseDir, entryPath string) string {
+	rel, err := filepath.Rel(baseDir, filepath.Dir(entryPath))
+	if err != nil {
+		return "."
+	}
+	return filepath.ToSlash(rel)
+}
+
+// rewriteAssetLinks rewrites relative image and link destinations in the
+// document so that they are relative to the base directory instead of the
+// entry's directory. It returns the rewritten asset paths (relative to the
+// base directory, slash-separated).
+func rewriteAssetLinks(doc ast.Node, relDir string) []string {
+	var assets []string
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		var dest *[]byte
+		switch node := n.(type) {
+		case *ast.Image:
+			dest = &node.Destination
+		case *ast.Link:
+			dest = &node.Destination
+		default:
+			return ast.WalkContinue, nil
+		}
+
+		rewritten, asset, ok := resolveAssetURL(string(*dest), relDir)
+		if ok {
+			*dest = []byte(rewritten)
+			assets = append(assets, asset)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return assets
+}
+
+// resolveAssetURL resolves a relative URL found in an entry located in relDir.
+// It returns the rewritten URL, the asset path relative to the base directory,
+// and false if the URL does not refer to a local asset.
+func resolveAssetURL(dest, relDir string) (string, string, bool) {
+	u, err := url.Parse(dest)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return "", "", false
+	}
+
+	asset := path.Join(relDir, u.Path)
+	if !isAssetPath(asset) {
+		return "", "", false
+	}
+
+	u.Path = asset
+	return u.String(), asset, true
+}
+
+// openAsset opens an asset relative to the base directory without following
+// paths or symlinks outside of it. Directories are rejected.
+func openAsset(baseDir, rel string) (*os.File, os.FileInfo, error) {
+	if !isAssetPath(rel) {
+		return nil, nil, os.ErrNotExist
+	}
+
+	root, err := os.OpenRoot(baseDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer root.Close()
+
+	f, err := root.Open(filepath.FromSlash(rel))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	if !info.Mode().IsRegular() {
+		f.Close()
+		return nil, nil, os.ErrNotExist
+	}
+
+	return f, info, nil
+}
+
+// handleAsset serves non-Markdown files under the base directory
+func (s *Server) handleAsset(w http.ResponseWriter, r *http.Request) {
+	rel := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+
+	f, info, err := openAsset(s.baseDir, rel)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+}
+
+// copyAssets copies the referenced assets from the base directory to the output directory.
+// Missing assets are skipped.
+func copyAssets(baseDir, outputDir string, assets map[string]struct{}) error {
+	for rel := range assets {
+		if err := copyAsset(baseDir, outputDir, rel); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("copying asset %s: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// copyAsset copies a single asset from the base directory to the output directory
+func copyAsset(baseDir, outputDir, rel string) error {
+	src, _, err := openAsset(baseDir, rel)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dstPath := filepath.Join(outputDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dstPath), config.DirPermission); err != nil {
+		return err
+	}
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsAssetPath(t *testing.T) {
+	tests := []struct {
+		name string
+		rel  string
+		want bool
+	}{
+		{name: "image", rel: "img/whiteboard.png", want: true},
+		{name: "nested image", rel: "2024/01/img/a.png", want: true},
+		{name: "markdown", rel: "2024-01-15.md", want: false},
+		{name: "markdown uppercase", rel: "2024-01-15.MD", want: false},
+		{name: "encrypted entry", rel: "2024-01-15.md.age", want: false},
+		{name: "dotfile", rel: ".env", want: false},
+		{name: "dot directory", rel: ".git/config", want: false},
+		{name: "traversal", rel: "../secret.png", want: false},
+		{name: "empty", rel: "", want: false},
+		{name: "current directory", rel: ".", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAssetPath(tt.rel); got != tt.want {
+				t.Errorf("isAssetPath(%q) = %v, want %v", tt.rel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveAssetURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		dest      string
+		relDir    string
+		wantURL   string
+		wantAsset string
+		wantOK    bool
+	}{
+		{name: "top level entry", dest: "img/a.png", relDir: ".", wantURL: "img/a.png", wantAsset: "img/a.png", wantOK: true},
+		{name: "nested entry", dest: "img/a.png", relDir: "2024/01", wantURL: "2024/01/img/a.png", wantAsset: "2024/01/img/a.png", wantOK: true},
+		{name: "parent directory", dest: "../shared/a.png", relDir: "2024", wantURL: "shared/a.png", wantAsset: "shared/a.png", wantOK: true},
+		{name: "escaped space", dest: "img/my%20file.png", relDir: "2024", wantURL: "2024/img/my%20file.png", wantAsset: "2024/img/my file.png", wantOK: true},
+		{name: "escapes base directory", dest: "../../a.png", relDir: "2024", wantOK: false},
+		{name: "absolute url", dest: "https://example.com/a.png", relDir: ".", wantOK: false},
+		{name: "absolute path", dest: "/a.png", relDir: ".", wantOK: false},
+		{name: "anchor", dest: "#2024-01-15", relDir: ".", wantOK: false},
+		{name: "markdown link", dest: "2024-01-14.md", relDir: ".", wantOK: false},
+		{name: "mailto", dest: "mailto:me@example.com", relDir: ".", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotURL, gotAsset, gotOK := resolveAssetURL(tt.dest, tt.relDir)
+			if gotOK != tt.wantOK {
+				t.Fatalf("resolveAssetURL(%q, %q) ok = %v, want %v", tt.dest, tt.relDir, gotOK, tt.wantOK)
+			}
+			if !tt.wantOK {
+				return
+			}
+			if gotURL != tt.wantURL {
+				t.Errorf("resolveAssetURL() url = %q, want %q", gotURL, tt.wantURL)
+			}
+			if gotAsset != tt.wantAsset {
+				t.Errorf("resolveAssetURL() asset = %q, want %q", gotAsset, tt.wantAsset)
+			}
+		})
+	}
+}
+
+func TestOpenAsset(t *testing.T) {
+	baseDir := t.TempDir()
+	outside := t.TempDir()
+
+	mustWrite(t, filepath.Join(baseDir, "img", "a.png"), "png")
+	mustWrite(t, filepath.Join(outside, "secret.png"), "secret")
+	if err := os.Symlink(filepath.Join(outside, "secret.png"), filepath.Join(baseDir, "link.png")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	f, _, err := openAsset(baseDir, "img/a.png")
+	if err != nil {
+		t.Fatalf("openAsset() error = %v", err)
+	}
+	f.Close()
+
+	for _, rel := range []string{"img", "link.png", "../secret.png", "missing.png"} {
+		if f, _, err := openAsset(baseDir, rel); err == nil {
+			f.Close()
+			t.Errorf("openAsset(%q) succeeded, want error", rel)
+		}
+	}
+}
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
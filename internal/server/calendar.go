@@ -0,0 +1,126 @@
+package server
+
+import (
+	"time"
+
+	"github.com/longkey1/jnal/internal/jnal"
+)
+
+// heatmapLevels is the number of intensity levels for days with entries
+const heatmapLevels = 4
+
+// CalendarDay represents a day in a heatmap or month calendar
+type CalendarDay struct {
+	Date time.Time
+	// InRange is false for padding days outside the year or month
+	InRange  bool
+	HasEntry bool
+	Words    int
+	// Level is the heatmap intensity: 0 without entry, 1 to 4 by word count
+	Level int
+	// EntryID is the id of the article of the day's first entry on the page
+	EntryID string
+}
+
+// Anchor returns the id of the day's first entry on the page,
+// or the date (yyyy-mm-dd) for days without entry
+func (d CalendarDay) Anchor() string {
+	if d.EntryID != "" {
+		return d.EntryID
+	}
+	return d.Date.Format("2006-01-02")
+}
+
+// CalendarWeek represents a week (Sunday to Saturday)
+type CalendarWeek struct {
+	Days []CalendarDay
+}
+
+// MonthCalendar represents a month calendar
+type MonthCalendar struct {
+	Label string
+	Weeks []CalendarWeek
+}
+
+// activityDay is the activity of a date with entries
+type activityDay struct {
+	words int
+	// entryID is the article id of the first of the date's entries
+	entryID string
+}
+
+// dayActivity holds the activity per date (yyyy-mm-dd) for days with entries
+type dayActivity map[string]activityDay
+
+// newDayActivity collects the dates with entries, in the order of the entries on the page
+func newDayActivity(entries jnal.Entries) dayActivity {
+	activity := make(dayActivity)
+	for _, e := range entries {
+		date := e.Date.Format("2006-01-02")
+		day, ok := activity[date]
+		if !ok {
+			day.entryID = entryAnchor(e.Path)
+		}
+		day.words += e.Words
+		activity[date] = day
+	}
+	return activity
+}
+
+// yearHeatmap builds a heatmap for the year, one column per week
+func (a dayActivity) yearHeatmap(year int) []CalendarWeek {
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	return a.weeks(first, last)
+}
+
+// monthCalendar builds a calendar for the month containing date
+func (a dayActivity) monthCalendar(date time.Time) *MonthCalendar {
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+	return &MonthCalendar{
+		Label: first.Format("January 2006"),
+		Weeks: a.weeks(first, last),
+	}
+}
+
+// weeks returns the full weeks covering first to last, with days outside the range marked
+func (a dayActivity) weeks(first, last time.Time) []CalendarWeek {
+	maxWords := 0
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		maxWords = max(maxWords, a[d.Format("2006-01-02")].words)
+	}
+
+	start := first.AddDate(0, 0, -int(first.Weekday()))
+	end := last.AddDate(0, 0, 6-int(last.Weekday()))
+
+	var weeks []CalendarWeek
+	for weekStart := start; !weekStart.After(end); weekStart = weekStart.AddDate(0, 0, 7) {
+		week := CalendarWeek{Days: make([]CalendarDay, 7)}
+		for i := range week.Days {
+			d := weekStart.AddDate(0, 0, i)
+			activity, ok := a[d.Format("2006-01-02")]
+			day := CalendarDay{
+				Date:    d,
+				InRange: !d.Before(first) && !d.After(last),
+			}
+			if ok && day.InRange {
+				day.HasEntry = true
+				day.Words = activity.words
+				day.Level = activityLevel(activity.words, maxWords)
+				day.EntryID = activity.entryID
+			}
+			week.Days[i] = day
+		}
+		weeks = append(weeks, week)
+	}
+	return weeks
+}
+
+// activityLevel returns the heatmap level for a day with an entry
+func activityLevel(words, maxWords int) int {
+	if maxWords == 0 {
+		return 1
+	}
+	return min(1+words*heatmapLevels/(maxWords+1), heatmapLevels)
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/longkey1/jnal/internal/jnal"
+)
+
+func TestDayActivity_MonthCalendar(t *testing.T) {
+	activity := newDayActivity(jnal.Entries{
+		{Path: "2024/2024-02-01-notes.md", Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Words: 10},
+		{Path: "2024/2024-02-01.md", Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Words: 5},
+		{Path: "2024/2024-02-29.md", Date: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), Words: 100},
+		{Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Words: 5},
+	})
+
+	cal := activity.monthCalendar(time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC))
+
+	// February 2024 starts on Thursday and ends on Thursday: 5 weeks
+	if len(cal.Weeks) != 5 {
+		t.Fatalf("len(Weeks) = %d, want 5", len(cal.Weeks))
+	}
+
+	first := cal.Weeks[0].Days[4]
+	// Days link to their first entry on the page
+	if !first.InRange || !first.HasEntry || first.Words != 15 || first.Anchor() != "2024-02-01-notes" {
+		t.Errorf("first day = %+v", first)
+	}
+	if cal.Weeks[0].Days[3].InRange {
+		t.Errorf("2024-01-31 should be outside the month")
+	}
+
+	last := cal.Weeks[4].Days[4]
+	if last.Anchor() != "2024-02-29" || last.Level != heatmapLevels {
+		t.Errorf("last day = %+v, want level %d", last, heatmapLevels)
+	}
+	// March 1st is padding even though it has an entry
+	if next := cal.Weeks[4].Days[5]; next.HasEntry {
+		t.Errorf("padding day has entry: %+v", next)
+	}
+}
+
+func TestDayActivity_YearHeatmap(t *testing.T) {
+	weeks := newDayActivity(nil).yearHeatmap(2024)
+	// 2024-01-01 is a Monday and 2024-12-31 a Tuesday: 53 weeks
+	if len(weeks) != 53 {
+		t.Errorf("len(weeks) = %d, want 53", len(weeks))
+	}
+}
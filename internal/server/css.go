@@ -0,0 +1,166 @@
+package server
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/longkey1/jnal/internal/cache"
+	"github.com/longkey1/jnal/internal/config"
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+//go:embed themes/*.css
+var themesFS embed.FS
+
+// cssCacheName is the name of the on-disk cache for downloaded stylesheets,
+// which is kept across upgrades
+const cssCacheName = "stylesheets"
+
+// cssCacheExpiry is how long a downloaded stylesheet is used before it is downloaded again
+const cssCacheExpiry = 24 * time.Hour
+
+// cssClient downloads stylesheets. The timeout keeps startup from hanging when offline.
+var cssClient = &http.Client{Timeout: 10 * time.Second}
+
+// defaultTheme is the name of DefaultCSS among the built-in themes
+const defaultTheme = "default"
+
+// lightDarkThemes are the built-in themes switching between light and dark colors,
+// following the system or the data-theme attribute set by the light/dark switch
+var lightDarkThemes = map[string]bool{"": true, defaultTheme: true, "minimal": true, "paper": true}
+
+// themeNamePattern matches css values naming a built-in theme
+var themeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// CSSThemes returns the names of the built-in stylesheets
+func CSSThemes() []string {
+	names := []string{defaultTheme}
+	files, _ := fs.Glob(themesFS, "themes/*.css")
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(filepath.Base(f), ".css"))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// stylesheets are the stylesheets of the generated pages
+type stylesheets struct {
+	css string
+	// cssDark replaces css when the dark color scheme is used
+	cssDark string
+	// darkModeToggle shows the light/dark switch
+	darkModeToggle bool
+}
+
+// loadStylesheets loads the stylesheets set by the build configuration.
+// The light/dark switch is shown when the stylesheets support both color schemes.
+func loadStylesheets(cfg *config.Config) (stylesheets, error) {
+	css, err := loadCSS(cfg.Build.CSS, cfg.Dir)
+	if err != nil {
+		return stylesheets{}, err
+	}
+
+	var cssDark string
+	if cfg.Build.CSSDark != "" {
+		if cssDark, err = loadCSS(cfg.Build.CSSDark, cfg.Dir); err != nil {
+			return stylesheets{}, fmt.Errorf("css_dark: %w", err)
+		}
+	}
+
+	return stylesheets{
+		css:            css,
+		cssDark:        cssDark,
+		darkModeToggle: cfg.Build.GetDarkModeToggle() && (cssDark != "" || lightDarkThemes[cfg.Build.CSS]),
+	}, nil
+}
+
+// loadCSS loads the stylesheet set by css: the default one when empty, a built-in theme
+// by name, a URL, a .css file path relative to dir, or inline CSS
+func loadCSS(css, dir string) (string, error) {
+	switch {
+	case css == "" || css == defaultTheme:
+		return DefaultCSS, nil
+	case strings.HasPrefix(css, "http://") || strings.HasPrefix(css, "https://"):
+		return fetchCSS(css)
+	case themeNamePattern.MatchString(css):
+		data, err := themesFS.ReadFile("themes/" + css + ".css")
+		if err != nil {
+			return "", fmt.Errorf("unknown css theme %q (available: %s)", css, strings.Join(CSSThemes(), ", "))
+		}
+		return string(data), nil
+	case strings.HasSuffix(css, ".css") && !strings.ContainsAny(css, "{}\n"):
+		path, err := homedir.Expand(css)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("reading CSS file: %w", err)
+		}
+		return string(data), nil
+	default:
+		// Inline CSS
+		return css, nil
+	}
+}
+
+// fetchCSS downloads the stylesheet at url, caching it on disk. A cached copy is used
+// until it expires, and after that whenever the download fails.
+func fetchCSS(url string) (string, error) {
+	c, err := cache.OpenShared(cssCacheName)
+	if err != nil {
+		// Downloading still works without the cache
+		fmt.Fprintf(os.Stderr, "Warning: css cache disabled: %v\n", err)
+	}
+
+	key := cache.Key([]byte(url))
+	cached, age, ok := c.GetWithAge(key)
+	if ok && age < cssCacheExpiry {
+		return string(cached), nil
+	}
+
+	data, err := downloadCSS(url)
+	if err != nil {
+		if ok {
+			fmt.Fprintf(os.Stderr, "Warning: %v; using the copy cached %s ago\n", err, age.Round(time.Minute))
+			return string(cached), nil
+		}
+		return "", err
+	}
+
+	if err := c.Put(key, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: writing css cache: %v\n", err)
+	}
+	return string(data), nil
+}
+
+// downloadCSS downloads the stylesheet at url
+func downloadCSS(url string) ([]byte, error) {
+	resp, err := cssClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetching CSS from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching CSS from %s: status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading CSS response: %w", err)
+	}
+	return data, nil
+}
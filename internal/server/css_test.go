@@ -0,0 +1,191 @@
+package server
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/longkey1/jnal/internal/config"
+)
+
+func TestLoadCSS(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "css", "portal.css"), "body { color: navy; }")
+
+	tests := []struct {
+		name    string
+		css     string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty", css: "", want: DefaultCSS},
+		{name: "default theme", css: "default", want: DefaultCSS},
+		{name: "theme", css: "paper", want: "/* paper:"},
+		{name: "unknown theme", css: "nope", wantErr: true},
+		{name: "relative file", css: "css/portal.css", want: "color: navy"},
+		{name: "absolute file", css: filepath.Join(dir, "css", "portal.css"), want: "color: navy"},
+		{name: "missing file", css: "missing.css", wantErr: true},
+		{name: "inline", css: "body { margin: 0; }", want: "body { margin: 0; }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadCSS(tt.css, dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadCSS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("loadCSS() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSSThemes(t *testing.T) {
+	for _, name := range CSSThemes() {
+		if _, err := loadCSS(name, ""); err != nil {
+			t.Errorf("loadCSS(%q) error = %v", name, err)
+		}
+	}
+}
+
+func TestFetchCSS_Cache(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+
+	requests := 0
+	online := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !online {
+			http.Error(w, "offline", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("body { color: red; }"))
+	}))
+	defer srv.Close()
+	url := srv.URL + "/style.css"
+
+	load := func(wantRequests int) {
+		t.Helper()
+		got, err := loadCSS(url, "")
+		if err != nil || got != "body { color: red; }" {
+			t.Fatalf("loadCSS() = %q, %v", got, err)
+		}
+		if requests != wantRequests {
+			t.Errorf("%d requests, want %d", requests, wantRequests)
+		}
+	}
+
+	load(1)
+	// The cached copy is used until it expires
+	load(1)
+
+	// An expired copy is used when the download fails
+	expire := func() {
+		old := time.Now().Add(-2 * cssCacheExpiry)
+		filepath.WalkDir(cacheDir, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				os.Chtimes(path, old, old)
+			}
+			return err
+		})
+	}
+	expire()
+	online = false
+	load(2)
+
+	// and downloaded again when possible
+	online = true
+	load(3)
+	load(3)
+}
+
+func TestFetchCSS_Timeout(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	client := cssClient
+	cssClient = &http.Client{Timeout: 50 * time.Millisecond}
+	defer func() { cssClient = client }()
+
+	if _, err := loadCSS(srv.URL, ""); err == nil {
+		t.Error("loadCSS() error = nil, want timeout")
+	}
+}
+
+func TestLoadStylesheets(t *testing.T) {
+	off := false
+	tests := []struct {
+		name       string
+		build      config.BuildConfig
+		wantDark   string
+		wantToggle bool
+	}{
+		{name: "default", wantToggle: true},
+		{name: "light and dark theme", build: config.BuildConfig{CSS: "paper"}, wantToggle: true},
+		{name: "dark only theme", build: config.BuildConfig{CSS: "terminal"}, wantToggle: false},
+		{name: "inline", build: config.BuildConfig{CSS: "body { color: red; }"}, wantToggle: false},
+		{
+			name:       "extra dark stylesheet",
+			build:      config.BuildConfig{CSS: "body { color: black; }", CSSDark: "body { color: white; }"},
+			wantDark:   "body { color: white; }",
+			wantToggle: true,
+		},
+		{name: "toggle disabled", build: config.BuildConfig{DarkModeToggle: &off}, wantToggle: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Build: tt.build}
+			got, err := loadStylesheets(cfg)
+			if err != nil {
+				t.Fatalf("loadStylesheets() error = %v", err)
+			}
+			if got.cssDark != tt.wantDark || got.darkModeToggle != tt.wantToggle {
+				t.Errorf("loadStylesheets() = dark %q, toggle %v, want %q, %v",
+					got.cssDark, got.darkModeToggle, tt.wantDark, tt.wantToggle)
+			}
+		})
+	}
+}
+
+func TestBuilder_Build_DarkStylesheet(t *testing.T) {
+	b, _ := newTestBuilder(t, map[string]string{"2024/2024-01-15.md": "Entry\n"})
+	b.cfg.Build.CSS = "body { color: black; }"
+	b.cfg.Build.CSSDark = "body { color: white; }"
+	styles, err := loadStylesheets(b.cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.stylesheets = styles
+
+	outputDir := t.TempDir()
+	if err := b.Build(outputDir); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	index, err := os.ReadFile(filepath.Join(outputDir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<style id="jnal-css" media="not screen, (prefers-color-scheme: light)">body { color: black; }</style>`,
+		`<style id="jnal-css-dark" media="screen and (prefers-color-scheme: dark)">body { color: white; }</style>`,
+		`onclick="jnalToggleColorScheme()"`,
+	} {
+		if !strings.Contains(string(index), want) {
+			t.Errorf("index.html missing %s", want)
+		}
+	}
+}
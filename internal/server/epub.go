@@ -0,0 +1,107 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/longkey1/jnal/internal/epub"
+	"github.com/longkey1/jnal/internal/util"
+)
+
+// epubCSS is the base stylesheet of EPUB books, kept simple for e-readers
+const epubCSS = `
+h1 { font-size: 1.6em; margin: 0 0 1em; }
+article { margin-bottom: 2em; }
+article h2 { font-size: 1.2em; border-bottom: 1px solid #999; }
+img { max-width: 100%; }
+pre { white-space: pre-wrap; font-size: 0.85em; }
+blockquote { border-left: 3px solid #999; margin-left: 0; padding-left: 1em; }
+`
+
+// BuildEPUB writes the entries within the range to w as an EPUB 3 book with one chapter per month.
+// Images referenced by entries are embedded; other attachments are left out.
+func (b *Builder) BuildEPUB(w io.Writer, r util.DateRange) error {
+	entries, err := b.journal.ListEntries()
+	if err != nil {
+		return fmt.Errorf("listing entries: %w", err)
+	}
+	entries = entries.Filter(r)
+	entries.SortByDateAsc()
+	entries, assets := b.renderEntries(entries)
+	if len(entries) == 0 {
+		return fmt.Errorf("no entries found")
+	}
+
+	years := groupForPrint(entries)
+	period := util.Format(entries[0].Date) + " – " + util.Format(entries[len(entries)-1].Date)
+
+	css := epubCSS
+	if b.cfg.Build.CSS != "" {
+		css += b.css
+	}
+
+	book := &epub.Book{
+		Title:      b.cfg.Build.Title,
+		Identifier: epubIdentifier(b.cfg.Build.Title, period),
+		Modified:   time.Now(),
+		CSS:        css,
+	}
+
+	for _, y := range years {
+		nav := epub.NavItem{Title: y.Year}
+		for _, m := range y.Months {
+			var body bytes.Buffer
+			if err := b.tmpl.ExecuteTemplate(&body, "epub-chapter", m); err != nil {
+				return fmt.Errorf("executing epub chapter template: %w", err)
+			}
+			chapter := epub.Chapter{Name: m.Anchor, Title: m.Label, Body: body.String()}
+			book.Chapters = append(book.Chapters, chapter)
+			nav.Children = append(nav.Children, epub.NavItem{Title: m.Label, Href: chapter.Href()})
+		}
+		book.Nav = append(book.Nav, nav)
+	}
+
+	book.Resources = b.epubResources(assets)
+
+	return book.Write(w)
+}
+
+// epubResources returns the existing images among the assets, sorted by path
+func (b *Builder) epubResources(assets map[string]struct{}) []epub.Resource {
+	var resources []epub.Resource
+	for rel := range assets {
+		if _, ok := epub.MediaType(rel); !ok {
+			continue
+		}
+		f, _, err := openAsset(b.baseDir, rel)
+		if err != nil {
+			continue
+		}
+		f.Close()
+
+		resources = append(resources, epub.Resource{
+			Path: rel,
+			Open: func() (io.ReadCloser, error) {
+				f, _, err := openAsset(b.baseDir, rel)
+				return f, err
+			},
+		})
+	}
+	slices.SortFunc(resources, func(a, b epub.Resource) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return resources
+}
+
+// epubIdentifier returns a stable URN for the book so that e-readers recognize re-exports
+func epubIdentifier(title, period string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + period))
+	sum[6] = sum[6]&0x0f | 0x50 // version 5 style
+	sum[8] = sum[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
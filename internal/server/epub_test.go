@@ -0,0 +1,75 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/longkey1/jnal/internal/util"
+)
+
+func TestBuilder_BuildEPUB(t *testing.T) {
+	b, _ := newTestBuilder(t, map[string]string{
+		"2023/2023-12-31.md": "Out of range\n",
+		"2024/2024-01-15.md": "First line\nsecond line\n\n---\n\n![photo](img/a.png) [slides](slides.pdf)\n",
+		"2024/2024-02-03.md": "# Later\n",
+		"2024/img/a.png":     "png",
+		"2024/slides.pdf":    "pdf",
+	})
+
+	r, err := util.ParseRange("2024-01-01", "2024-12-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := b.BuildEPUB(&buf, r); err != nil {
+		t.Fatalf("BuildEPUB() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	want := []string{
+		"mimetype", "META-INF/container.xml", "OEBPS/content.opf", "OEBPS/nav.xhtml", "OEBPS/style.css",
+		"OEBPS/2024-01.xhtml", "OEBPS/2024-02.xhtml", "OEBPS/2024/img/a.png",
+	}
+	if strings.Join(names, " ") != strings.Join(want, " ") {
+		t.Errorf("BuildEPUB() files = %v, want %v", names, want)
+	}
+
+	chapter := readZipFile(t, zr, "OEBPS/2024-01.xhtml")
+	d := xml.NewDecoder(strings.NewReader(chapter))
+	d.Strict = true
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("chapter is not well-formed XML: %v\n%s", err, chapter)
+		}
+	}
+	if !strings.Contains(chapter, `<img src="2024/img/a.png" alt="photo" />`) {
+		t.Errorf("chapter does not reference the embedded image:\n%s", chapter)
+	}
+}
+
+func readZipFile(t *testing.T, zr *zip.Reader, name string) string {
+	t.Helper()
+	f, err := zr.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
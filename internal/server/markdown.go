@@ -0,0 +1,236 @@
+package server
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/util"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// headingShifter shifts the levels of headings in entries, clamped to h6,
+// so that they nest below the page, year, month and date headings
+type headingShifter struct {
+	shift int
+}
+
+// Transform implements parser.ASTTransformer
+func (t *headingShifter) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if h, ok := n.(*ast.Heading); ok && entering {
+			h.Level = min(h.Level+t.shift, 6)
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// linkTargetSetter makes links to other sites open in a new tab
+type linkTargetSetter struct{}
+
+// Transform implements parser.ASTTransformer
+func (t *linkTargetSetter) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		var dest []byte
+		switch node := n.(type) {
+		case *ast.Link:
+			dest = node.Destination
+		case *ast.AutoLink:
+			dest = node.URL(source)
+		default:
+			return ast.WalkContinue, nil
+		}
+
+		if isExternalLink(string(dest)) {
+			n.SetAttributeString("target", []byte("_blank"))
+			n.SetAttributeString("rel", []byte("noopener noreferrer"))
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// isExternalLink reports whether a link destination points to another site, such as
+// http://, https:// and protocol-relative // URLs. Anchors and relative links to
+// attachments and other local files are not external.
+func isExternalLink(dest string) bool {
+	u, err := url.Parse(dest)
+	return err == nil && u.Host != ""
+}
+
+// headingIDsKey is the parser context key of the headingIDs of the entry being converted
+var headingIDsKey = parser.NewContextKey()
+
+// tocHeading is a heading listed in the table of contents of an entry
+type tocHeading struct {
+	ID    string
+	Text  string
+	Level int
+}
+
+// headingIDs assigns heading IDs that are unique within an entry. IDs are the entry anchor
+// and the heading slug joined by "--", which neither contains, so that they don't collide
+// with other entries or their headings on pages listing several entries.
+type headingIDs struct {
+	prefix   string
+	used     map[string]bool
+	headings []tocHeading
+	// private is set while converting a private block. Its headings are left out of the
+	// table of contents, and their IDs are marked with private_ so that the other IDs are
+	// the same whether private blocks are shown or not.
+	private bool
+}
+
+// newHeadingIDs creates a headingIDs for an entry whose IDs start with prefix
+func newHeadingIDs(prefix string) *headingIDs {
+	return &headingIDs{prefix: prefix, used: make(map[string]bool)}
+}
+
+// assign returns a unique ID for a heading and records public headings for the table of contents
+func (h *headingIDs) assign(text string, level int) string {
+	base := slugify(text)
+	if base == "" {
+		base = "section"
+	}
+	// Slugs never contain an underscore
+	if h.private {
+		base = "private_" + base
+	}
+	if h.prefix != "" {
+		base = h.prefix + "--" + base
+	}
+
+	id := base
+	for n := 1; h.used[id]; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	h.used[id] = true
+	if !h.private {
+		h.headings = append(h.headings, tocHeading{ID: id, Text: text, Level: level})
+	}
+	return id
+}
+
+// tableOfContents returns the table of contents of the headings as nested lists,
+// or "" if there are fewer than minHeadings headings
+func (h *headingIDs) tableOfContents(minHeadings int) string {
+	if len(h.headings) == 0 || len(h.headings) < minHeadings {
+		return ""
+	}
+
+	top := h.headings[0].Level
+	for _, heading := range h.headings {
+		top = min(top, heading.Level)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<details class=\"toc\">\n<summary>Contents</summary>\n")
+	depth := 0
+	for _, heading := range h.headings {
+		level := heading.Level - top + 1
+		if level > depth {
+			for ; depth < level; depth++ {
+				sb.WriteString("<ul>\n<li>")
+			}
+		} else {
+			sb.WriteString("</li>\n")
+			for ; depth > level; depth-- {
+				sb.WriteString("</ul>\n</li>\n")
+			}
+			sb.WriteString("<li>")
+		}
+		fmt.Fprintf(&sb, "<a href=\"#%s\">%s</a>", html.EscapeString(heading.ID), html.EscapeString(heading.Text))
+	}
+	for ; depth > 0; depth-- {
+		sb.WriteString("</li>\n</ul>\n")
+	}
+	sb.WriteString("</details>\n")
+	return sb.String()
+}
+
+// entryAnchor returns the anchor of the entry at path, which also prefixes its heading IDs:
+// the slugified file name from the date on, without extension, so that further entries on
+// the same day keep their number (2024-01-15-2)
+func entryAnchor(path string) string {
+	name := filepath.Base(strings.TrimSuffix(path, jnal.EncryptedExt))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	if loc := util.DatePattern.FindStringIndex(name); loc != nil {
+		name = name[loc[0]:]
+	}
+	return slugify(name)
+}
+
+// headingIDSetter sets the IDs of headings from the headingIDs in the parser context.
+// Headings get no ID when the context has none.
+type headingIDSetter struct{}
+
+// Transform implements parser.ASTTransformer
+func (t *headingIDSetter) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	ids, ok := pc.Get(headingIDsKey).(*headingIDs)
+	if !ok {
+		return
+	}
+
+	source := reader.Source()
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if h, ok := n.(*ast.Heading); ok && entering {
+			h.SetAttributeString("id", []byte(ids.assign(nodeText(h, source), h.Level)))
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// nodeText returns the plain text of an inline node and its children
+func nodeText(n ast.Node, source []byte) string {
+	var sb strings.Builder
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := c.(type) {
+		case *ast.Text:
+			sb.Write(node.Segment.Value(source))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(node.Value)
+		case *ast.AutoLink:
+			sb.Write(node.Label(source))
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(sb.String())
+}
+
+// slugify turns heading text into an ID: lowercase letters and digits
+// of any script, with other runs of characters replaced by a hyphen
+func slugify(s string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			hyphen = false
+			sb.WriteRune(r)
+		} else {
+			hyphen = true
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,262 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/jnal"
+)
+
+func TestEntryRenderer_HeadingsAndLinks(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "heading shift",
+			markdown: "# One\n\n### Three\n",
+			want:     "<h5>One</h5>\n<h6>Three</h6>\n",
+		},
+		{
+			name:     "headings in code blocks",
+			markdown: "```html\n<h1>Title</h1>\n<a href=\"https://example.com\">x</a>\n```\n",
+			want:     "<pre><code class=\"language-html\">&lt;h1&gt;Title&lt;/h1&gt;\n&lt;a href=&quot;https://example.com&quot;&gt;x&lt;/a&gt;\n</code></pre>\n",
+		},
+		{
+			name:     "external link",
+			markdown: "[site](https://example.com \"Example\")\n",
+			want:     "<p><a href=\"https://example.com\" title=\"Example\" target=\"_blank\" rel=\"noopener noreferrer\">site</a></p>\n",
+		},
+		{
+			name:     "same-page anchor",
+			markdown: "[see above](#2024-01-14)\n",
+			want:     "<p><a href=\"#2024-01-14\">see above</a></p>\n",
+		},
+		{
+			name:     "relative link",
+			markdown: "[doc](attachments/x.pdf)\n",
+			want:     "<p><a href=\"attachments/x.pdf\">doc</a></p>\n",
+		},
+		{
+			name:     "protocol-relative link",
+			markdown: "[site](//example.com/page)\n",
+			want:     "<p><a href=\"//example.com/page\" target=\"_blank\" rel=\"noopener noreferrer\">site</a></p>\n",
+		},
+		{
+			name:     "email link",
+			markdown: "[mail](mailto:me@example.com)\n",
+			want:     "<p><a href=\"mailto:me@example.com\">mail</a></p>\n",
+		},
+		{
+			name:     "autolink",
+			markdown: "Visit https://example.com today\n",
+			want:     "<p>Visit <a href=\"https://example.com\" target=\"_blank\" rel=\"noopener noreferrer\">https://example.com</a> today</p>\n",
+		},
+		{
+			name:     "link in nested content",
+			markdown: "> - [site](https://example.com)\n",
+			want:     "<blockquote>\n<ul>\n<li><a href=\"https://example.com\" target=\"_blank\" rel=\"noopener noreferrer\">site</a></li>\n</ul>\n</blockquote>\n",
+		},
+	}
+
+	cfg := &config.Config{}
+	cfg.SetDefaults()
+	r := newEntryRenderer(cfg, jnal.NewJournal(cfg), t.TempDir(), false)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.convertMarkdown(tt.markdown, ".", nil)
+			if err != nil {
+				t.Fatalf("convertMarkdown() error = %v", err)
+			}
+			if got.HTML != tt.want {
+				t.Errorf("HTML = %q, want %q", got.HTML, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryRenderer_HeadingsAndLinksDisabled(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	shift := 0
+	targetBlank := false
+	cfg := &config.Config{Build: config.BuildConfig{HeadingShift: &shift, LinkTargetBlank: &targetBlank}}
+	cfg.SetDefaults()
+	r := newEntryRenderer(cfg, jnal.NewJournal(cfg), t.TempDir(), false)
+
+	got, err := r.convertMarkdown("# One\n\n[site](https://example.com)\n", ".", nil)
+	if err != nil {
+		t.Fatalf("convertMarkdown() error = %v", err)
+	}
+	want := "<h1>One</h1>\n<p><a href=\"https://example.com\">site</a></p>\n"
+	if got.HTML != want {
+		t.Errorf("HTML = %q, want %q", got.HTML, want)
+	}
+}
+
+func TestEntryRenderer_HeadingIDs(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	tests := []struct {
+		name     string
+		toc      bool
+		markdown string
+		want     string
+	}{
+		{
+			name:     "unique IDs",
+			markdown: "# Retro notes\n\n## Retro notes\n\n## Retro notes 1\n\n## Retro notes\n",
+			want: "<h5 id=\"2024-01-15--retro-notes\">Retro notes</h5>\n" +
+				"<h6 id=\"2024-01-15--retro-notes-1\">Retro notes</h6>\n" +
+				"<h6 id=\"2024-01-15--retro-notes-1-1\">Retro notes 1</h6>\n" +
+				"<h6 id=\"2024-01-15--retro-notes-2\">Retro notes</h6>\n",
+		},
+		{
+			name:     "inline markup and other scripts",
+			markdown: "# `go test` & *振り返り*!\n\n# ???\n",
+			want: "<h5 id=\"2024-01-15--go-test-振り返り\"><code>go test</code> &amp; <em>振り返り</em>!</h5>\n" +
+				"<h5 id=\"2024-01-15--section\">???</h5>\n",
+		},
+		{
+			name:     "table of contents",
+			toc:      true,
+			markdown: "# One\n\n## Two\n\n# Three\n",
+			want: "<details class=\"toc\">\n<summary>Contents</summary>\n" +
+				"<ul>\n<li><a href=\"#2024-01-15--one\">One</a><ul>\n<li><a href=\"#2024-01-15--two\">Two</a></li>\n</ul>\n</li>\n" +
+				"<li><a href=\"#2024-01-15--three\">Three</a></li>\n</ul>\n</details>\n" +
+				"<h5 id=\"2024-01-15--one\">One</h5>\n<h6 id=\"2024-01-15--two\">Two</h6>\n<h5 id=\"2024-01-15--three\">Three</h5>\n",
+		},
+		{
+			name:     "too few headings for a table of contents",
+			toc:      true,
+			markdown: "# One\n\n# Two\n",
+			want:     "<h5 id=\"2024-01-15--one\">One</h5>\n<h5 id=\"2024-01-15--two\">Two</h5>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Build: config.BuildConfig{TOC: tt.toc}}
+			cfg.SetDefaults()
+			r := newEntryRenderer(cfg, jnal.NewJournal(cfg), t.TempDir(), false)
+
+			got, err := r.convert([]byte(tt.markdown), ".", "2024-01-15")
+			if err != nil {
+				t.Fatalf("convert() error = %v", err)
+			}
+			if got.HTML != tt.want {
+				t.Errorf("HTML = %q, want %q", got.HTML, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Retro Notes", "retro-notes"},
+		{"  What's next?  ", "what-s-next"},
+		{"Q1 2024 / Plans", "q1-2024-plans"},
+		{"日記 メモ", "日記-メモ"},
+		{"!!!", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := slugify(tt.input); got != tt.want {
+				t.Errorf("slugify(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryRenderer_TableOfContentsPrivate(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	markdown := "# Plans\n\n# Notes\n\n:::private\n# Divorce lawyer\n\n# Notes\n:::\n\n# Notes\n"
+
+	tests := []struct {
+		name        string
+		showPrivate bool
+		want        []string
+		notWant     []string
+	}{
+		{
+			name:        "private shown",
+			showPrivate: true,
+			want: []string{
+				`<a href="#2024-01-15--notes-1">Notes</a>`,
+				`<div class="private-block">` + "\n" + `<h5 id="2024-01-15--private_divorce-lawyer">Divorce lawyer</h5>`,
+				`<h5 id="2024-01-15--private_notes">Notes</h5>`,
+			},
+			notWant: []string{`href="#2024-01-15--private_`},
+		},
+		{
+			name: "private left out",
+			want: []string{
+				`<a href="#2024-01-15--notes-1">Notes</a>`,
+			},
+			notWant: []string{"Divorce lawyer", "private_"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Build: config.BuildConfig{TOC: true}}
+			cfg.SetDefaults()
+			r := newEntryRenderer(cfg, jnal.NewJournal(cfg), t.TempDir(), tt.showPrivate)
+
+			got, err := r.convert([]byte(markdown), ".", "2024-01-15")
+			if err != nil {
+				t.Fatalf("convert() error = %v", err)
+			}
+			toc, _, ok := strings.Cut(got.HTML, "</details>")
+			if !ok {
+				t.Fatalf("HTML has no table of contents: %q", got.HTML)
+			}
+			if strings.Contains(toc, "Divorce lawyer") {
+				t.Errorf("table of contents lists a private heading: %q", toc)
+			}
+			// Public headings have the same IDs whether private blocks are shown or not
+			for _, id := range []string{"2024-01-15--plans", "2024-01-15--notes", "2024-01-15--notes-1"} {
+				if !strings.Contains(got.HTML, `<h5 id="`+id+`">`) {
+					t.Errorf("HTML missing heading %q", id)
+				}
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got.HTML, s) {
+					t.Errorf("HTML missing %q:\n%s", s, got.HTML)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got.HTML, s) {
+					t.Errorf("HTML contains %q:\n%s", s, got.HTML)
+				}
+			}
+		})
+	}
+}
+
+func TestEntryAnchor(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/journal/2024/2024-01-15.md", "2024-01-15"},
+		{"/journal/2024/2024-01-15-2.md", "2024-01-15-2"},
+		{"/journal/2024/2024-01-15-2.md.age", "2024-01-15-2"},
+		{"/journal/2024/journal-2024-01-15.md", "2024-01-15"},
+		{"/journal/2024/2024-01-15 Team Retro.md", "2024-01-15-team-retro"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := entryAnchor(tt.path); got != tt.want {
+				t.Errorf("entryAnchor(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/util"
+)
+
+// OnThisDayData represents data for the onthisday template
+type OnThisDayData struct {
+	Title          string
+	CSS            template.CSS
+	CSSDark        template.CSS
+	DarkModeToggle bool
+	Pages          []PageLink
+	Date           time.Time
+	Prev           time.Time
+	Next           time.Time
+	Memories       []MemoryView
+	LiveReload     bool
+}
+
+// MemoryView represents a past entry on the onthisday page
+type MemoryView struct {
+	Label string
+	Entry TemplateEntry
+}
+
+// handleOnThisDay handles the onthisday page.
+// The date defaults to today; week, month and year query parameters
+// include the same-weekday entries as in the onthisday command.
+func (s *Server) handleOnThisDay(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	date := util.Today()
+	if d := query.Get("date"); d != "" {
+		parsed, err := util.Parse(d)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		date = parsed
+	}
+
+	s.mu.RLock()
+	entries := s.entries
+	s.mu.RUnlock()
+
+	memories := jnal.FindOnThisDay(entries, date, jnal.OnThisDayOptions{
+		WeekAgo:  query.Get("week") != "",
+		MonthAgo: query.Get("month") != "",
+		YearAgo:  query.Get("year") != "",
+	})
+
+	today := util.Today()
+	views := make([]MemoryView, len(memories))
+	for i, m := range memories {
+		views[i] = MemoryView{
+			Label: m.Label,
+			Entry: TemplateEntry{
+				ID:        entryAnchor(m.Entry.Path),
+				Date:      m.Entry.Date,
+				Content:   template.HTML(m.Entry.Content),
+				Words:     m.Entry.Words,
+				Private:   m.Entry.Private,
+				Draft:     m.Entry.Publish.Draft,
+				Scheduled: scheduledDate(m.Entry, today),
+			},
+		}
+	}
+
+	data := OnThisDayData{
+		Title:          s.cfg.Build.Title,
+		CSS:            template.CSS(s.css),
+		CSSDark:        template.CSS(s.cssDark),
+		DarkModeToggle: s.darkModeToggle,
+		Pages:          s.pages(),
+		Date:           date,
+		Prev:           date.AddDate(0, 0, -1),
+		Next:           date.AddDate(0, 0, 1),
+		Memories:       views,
+		LiveReload:     s.liveReload,
+	}
+
+	if err := s.tmpl.ExecuteTemplate(w, "onthisday.html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
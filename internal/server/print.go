@@ -0,0 +1,93 @@
+package server
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/util"
+)
+
+// PrintData represents data for the print template
+type PrintData struct {
+	Title string
+	// Period describes the dates covered by the book (e.g. "2024-01-01 – 2024-12-31")
+	Period string
+	CSS    template.CSS
+	Years  []PrintYear
+}
+
+// PrintYear represents a year in the print book
+type PrintYear struct {
+	Year   string
+	Months []PrintMonth
+}
+
+// PrintMonth represents a month in the print book, starting on a new page
+type PrintMonth struct {
+	// Anchor is the id of the month's section (yyyy-mm)
+	Anchor  string
+	Label   string
+	Entries []TemplateEntry
+}
+
+// BuildPrint generates a print-ready book of the entries within the range to the output directory.
+// Entries are always in chronological order, grouped by year and month.
+func (b *Builder) BuildPrint(outputDir string, r util.DateRange) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	entries, err := b.journal.ListEntries()
+	if err != nil {
+		return fmt.Errorf("listing entries: %w", err)
+	}
+	entries = entries.Filter(r)
+	entries.SortByDateAsc()
+
+	entries, assets := b.renderEntries(entries)
+	if err := copyAssets(b.baseDir, outputDir, assets); err != nil {
+		return fmt.Errorf("copying assets: %w", err)
+	}
+
+	data := PrintData{
+		Title: b.cfg.Build.Title,
+		CSS:   template.CSS(b.css),
+		Years: groupForPrint(entries),
+	}
+	if len(entries) > 0 {
+		data.Period = util.Format(entries[0].Date) + " – " + util.Format(entries[len(entries)-1].Date)
+	}
+
+	return b.writePage(outputDir, "print.html", data)
+}
+
+// groupForPrint groups entries sorted in chronological order by year and month
+func groupForPrint(entries jnal.Entries) []PrintYear {
+	var years []PrintYear
+	for _, e := range entries {
+		year := e.Date.Format("2006")
+		if len(years) == 0 || years[len(years)-1].Year != year {
+			years = append(years, PrintYear{Year: year})
+		}
+
+		y := &years[len(years)-1]
+		anchor := e.Date.Format("2006-01")
+		if len(y.Months) == 0 || y.Months[len(y.Months)-1].Anchor != anchor {
+			y.Months = append(y.Months, PrintMonth{
+				Anchor: anchor,
+				Label:  e.Date.Format("January 2006"),
+			})
+		}
+
+		m := &y.Months[len(y.Months)-1]
+		m.Entries = append(m.Entries, TemplateEntry{
+			ID:      entryAnchor(e.Path),
+			Date:    e.Date,
+			Content: template.HTML(e.Content),
+			Words:   e.Words,
+		})
+	}
+	return years
+}
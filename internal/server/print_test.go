@@ -0,0 +1,82 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/util"
+)
+
+// newTestBuilder creates a Builder for a journal in a temporary directory, without the render cache
+func newTestBuilder(t *testing.T, files map[string]string) (*Builder, string) {
+	t.Helper()
+	baseDir := t.TempDir()
+	for name, content := range files {
+		mustWrite(t, filepath.Join(baseDir, name), content)
+	}
+
+	cache := false
+	cfg := &config.Config{
+		Common: config.CommonConfig{BaseDirectory: baseDir, PathFormat: "2006/2006-01-02.md"},
+		Build:  config.BuildConfig{Cache: &cache},
+	}
+	cfg.SetDefaults()
+
+	b, err := NewBuilder(cfg, jnal.NewJournal(cfg), baseDir)
+	if err != nil {
+		t.Fatalf("NewBuilder() error = %v", err)
+	}
+	return b, baseDir
+}
+
+func TestBuilder_BuildPrint(t *testing.T) {
+	b, _ := newTestBuilder(t, map[string]string{
+		"2023/2023-12-31.md": "Out of range\n",
+		"2024/2024-02-03.md": "# Later\n\nSecond month\n",
+		"2024/2024-01-15.md": "First month ![photo](img/a.png)\n",
+		"2024/img/a.png":     "png",
+	})
+
+	r, err := util.ParseRange("2024-01-01", "2024-12-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputDir := t.TempDir()
+	if err := b.BuildPrint(outputDir, r); err != nil {
+		t.Fatalf("BuildPrint() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+
+	for _, want := range []string{
+		`<p class="period">2024-01-15 – 2024-02-03</p>`,
+		`<a href="#2024-01">January 2024`,
+		`<section class="month" id="2024-02">`,
+		`<h2 id="2024">2024</h2>`,
+		"@media print",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("BuildPrint() output missing %q", want)
+		}
+	}
+	if strings.Contains(html, "Out of range") {
+		t.Error("BuildPrint() output contains an entry outside the range")
+	}
+	if strings.Index(html, "First month") > strings.Index(html, "Second month") {
+		t.Error("BuildPrint() entries are not in chronological order")
+	}
+	if strings.Count(html, `<h2 id="2024">`) != 1 {
+		t.Error("BuildPrint() repeats the year heading")
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "2024", "img", "a.png")); err != nil {
+		t.Errorf("BuildPrint() did not copy the asset: %v", err)
+	}
+}
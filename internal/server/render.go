@@ -0,0 +1,245 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/longkey1/jnal/internal/cache"
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// renderCacheName is the name of the on-disk cache for rendered entries
+const renderCacheName = "render"
+
+// renderFormat is bumped whenever renderedEntry or the rendering pipeline changes
+const renderFormat = 11
+
+// renderedEntry is the result of rendering a journal entry
+type renderedEntry struct {
+	HTML   string   `json:"html"`
+	Assets []string `json:"assets"`
+	Words  int      `json:"words"`
+	// Private is set for entries marked private, which have no content unless shown
+	Private bool `json:"private"`
+	// Publish is the draft and publish date metadata of the entry
+	Publish jnal.PublishStatus `json:"publish"`
+}
+
+// entryRenderer converts journal entries to HTML
+type entryRenderer struct {
+	cfg     *config.Config
+	journal *jnal.Journal
+	baseDir string
+	md      goldmark.Markdown
+	cache   *cache.Cache
+	// showPrivate renders private entries and blocks instead of leaving them out
+	showPrivate bool
+}
+
+// newEntryRenderer creates a new entryRenderer configured by the build configuration.
+// Private content is only rendered when showPrivate is set.
+func newEntryRenderer(cfg *config.Config, jnl *jnal.Journal, baseDir string, showPrivate bool) *entryRenderer {
+	// Configure goldmark. XHTML output keeps entries valid in EPUB content documents.
+	rendererOpts := []renderer.Option{html.WithXHTML()}
+	if cfg.Build.GetHardWraps() {
+		rendererOpts = append(rendererOpts, html.WithHardWraps())
+	}
+	transformers := []util.PrioritizedValue{util.Prioritized(&headingIDSetter{}, 100)}
+	if shift := cfg.Build.GetHeadingShift(); shift > 0 {
+		transformers = append(transformers, util.Prioritized(&headingShifter{shift: shift}, 100))
+	}
+	if cfg.Build.GetLinkTargetBlank() {
+		transformers = append(transformers, util.Prioritized(&linkTargetSetter{}, 100))
+	}
+	opts := []goldmark.Option{
+		goldmark.WithRendererOptions(rendererOpts...),
+		goldmark.WithParserOptions(parser.WithASTTransformers(transformers...)),
+	}
+	if cfg.Build.GetLinkify() {
+		opts = append(opts, goldmark.WithExtensions(extension.Linkify))
+	}
+
+	r := &entryRenderer{
+		cfg:         cfg,
+		journal:     jnl,
+		baseDir:     baseDir,
+		md:          goldmark.New(opts...),
+		showPrivate: showPrivate,
+	}
+
+	if cfg.Build.GetCache() {
+		c, err := cache.Open(renderCacheName)
+		if err != nil {
+			// Rendering still works without the cache
+			fmt.Fprintf(os.Stderr, "Warning: render cache disabled: %v\n", err)
+		} else {
+			r.cache = c
+		}
+	}
+
+	return r
+}
+
+// render loads and converts the entry at path to HTML, using the cache when possible.
+// Encrypted entries are never cached, so that their plaintext stays off the disk.
+func (r *entryRenderer) render(path string) (*renderedEntry, error) {
+	data, err := r.journal.ReadEntry(path)
+	if err != nil {
+		return nil, err
+	}
+
+	relDir := entryRelDir(r.baseDir, path)
+	idPrefix := entryAnchor(path)
+	if jnal.IsEncrypted(path) {
+		return r.convert(data, relDir, idPrefix)
+	}
+	key := cache.Key(data, []byte(relDir), []byte(idPrefix), []byte(r.settings()))
+
+	if cached, ok := r.cache.Get(key); ok {
+		var entry renderedEntry
+		if err := json.Unmarshal(cached, &entry); err == nil {
+			return &entry, nil
+		}
+	}
+
+	entry, err := r.convert(data, relDir, idPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if encoded, err := json.Marshal(entry); err == nil {
+		if err := r.cache.Put(key, encoded); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: writing render cache: %v\n", err)
+		}
+	}
+
+	return entry, nil
+}
+
+// renderAll renders entries concurrently using the given number of workers.
+// Results are returned in entry order; entries that failed to render are nil.
+func (r *entryRenderer) renderAll(entries jnal.Entries, jobs int) []*renderedEntry {
+	results := make([]*renderedEntry, len(entries))
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(jobs, len(entries)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				entry, err := r.render(entries[i].Path)
+				if err != nil {
+					continue
+				}
+				results[i] = entry
+			}
+		}()
+	}
+
+	for i := range entries {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// convert converts markdown to HTML for an entry located in relDir, with heading IDs
+// starting with idPrefix. Front matter is metadata and not part of the rendered entry.
+// Private entries and blocks are left out unless private content is shown, in which case
+// private blocks are wrapped in a private-block element and left out of the table of
+// contents. The draft and publish date metadata is returned for the caller to decide
+// whether the entry is published.
+func (r *entryRenderer) convert(data []byte, relDir, idPrefix string) (*renderedEntry, error) {
+	content := string(data)
+	private := jnal.IsPrivateEntry(content)
+	if private && !r.showPrivate {
+		return &renderedEntry{Private: true}, nil
+	}
+	publish := jnal.ParsePublishStatus(content)
+	body := jnal.StripFrontMatter(content)
+	ids := newHeadingIDs(idPrefix)
+
+	if !r.showPrivate {
+		entry, err := r.convertMarkdown(jnal.RedactPrivate(body), relDir, ids)
+		if err != nil {
+			return nil, err
+		}
+		entry.HTML = r.tableOfContents(ids) + entry.HTML
+		entry.Publish = publish
+		return entry, nil
+	}
+
+	entry := &renderedEntry{Private: private, Publish: publish}
+	var sb strings.Builder
+	for _, segment := range jnal.SplitPrivate(body) {
+		ids.private = segment.Private
+		rendered, err := r.convertMarkdown(segment.Text, relDir, ids)
+		if err != nil {
+			return nil, err
+		}
+		if segment.Private {
+			sb.WriteString(`<div class="private-block">` + "\n" + rendered.HTML + "</div>\n")
+		} else {
+			sb.WriteString(rendered.HTML)
+		}
+		entry.Assets = append(entry.Assets, rendered.Assets...)
+		entry.Words += rendered.Words
+	}
+	entry.HTML = r.tableOfContents(ids) + sb.String()
+	return entry, nil
+}
+
+// tableOfContents returns the table of contents of an entry with the headings in ids,
+// or "" if tables of contents are disabled or the entry has too few headings
+func (r *entryRenderer) tableOfContents(ids *headingIDs) string {
+	if !r.cfg.Build.TOC {
+		return ""
+	}
+	return ids.tableOfContents(r.cfg.Build.GetTOCMinHeadings())
+}
+
+// convertMarkdown converts a markdown body to HTML for an entry located in relDir.
+// Heading IDs are assigned by ids, which is shared by the parts of an entry; with nil ids
+// headings get no IDs.
+func (r *entryRenderer) convertMarkdown(body, relDir string, ids *headingIDs) (*renderedEntry, error) {
+	data := []byte(body)
+	pc := parser.NewContext()
+	if ids != nil {
+		pc.Set(headingIDsKey, ids)
+	}
+	doc := r.md.Parser().Parse(text.NewReader(data), parser.WithContext(pc))
+	assets := rewriteAssetLinks(doc, relDir)
+
+	var buf bytes.Buffer
+	if err := r.md.Renderer().Render(&buf, data, doc); err != nil {
+		return nil, err
+	}
+
+	return &renderedEntry{HTML: buf.String(), Assets: assets, Words: jnal.CountWords(body)}, nil
+}
+
+// settings returns a description of all settings affecting the rendered output
+func (r *entryRenderer) settings() string {
+	b := r.cfg.Build
+	return fmt.Sprintf("format=%d heading_shift=%d linkify=%t hard_wraps=%t link_target_blank=%t show_private=%t toc=%t toc_min_headings=%d",
+		renderFormat, b.GetHeadingShift(), b.GetLinkify(), b.GetHardWraps(), b.GetLinkTargetBlank(), r.showPrivate,
+		b.TOC, b.GetTOCMinHeadings())
+}
@@ -0,0 +1,331 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/crypt"
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/util"
+)
+
+func TestBuilder_Build_EncryptedNotCached(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+
+	baseDir := t.TempDir()
+	identity, _, err := crypt.GenerateIdentityFile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	identityFile := filepath.Join(t.TempDir(), "identity.txt")
+	mustWrite(t, identityFile, string(identity))
+	mustWrite(t, filepath.Join(baseDir, "2024-01-14.md"), "Plain entry\n")
+
+	cfg := &config.Config{
+		Common: config.CommonConfig{BaseDirectory: baseDir},
+		Encryption: config.EncryptionConfig{
+			Enabled:      true,
+			IdentityFile: identityFile,
+		},
+	}
+	cfg.SetDefaults()
+	jnl := jnal.NewJournal(cfg)
+	if err := jnl.AppendEntry(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), "Secret diagnosis"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewBuilder(cfg, jnl, baseDir)
+	if err != nil {
+		t.Fatalf("NewBuilder() error = %v", err)
+	}
+	outputDir := t.TempDir()
+	if err := b.Build(outputDir); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(outputDir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), "Secret diagnosis") {
+		t.Error("encrypted entry not rendered")
+	}
+
+	cached := 0
+	err = filepath.WalkDir(cacheDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		cached++
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(data, []byte("Secret")) {
+			t.Errorf("plaintext of encrypted entry in cache file %s", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached != 1 {
+		t.Errorf("%d cache files, want 1 for the plain entry", cached)
+	}
+}
+
+func TestBuilder_Build_Private(t *testing.T) {
+	files := map[string]string{
+		"2024/2024-01-14.md": "---\nprivate: true\n---\nWhole entry is confidential ![x](img/private.png)\n",
+		"2024/2024-01-15.md": "Public note\n\n:::private\nSalary negotiation ![y](img/secret.png)\n:::\n\n" +
+			"<!-- private -->\nDoctor appointment\n<!-- /private -->\n\nMore public ![z](img/public.png)\n",
+		// Inline code at the start of a line doesn't open a code block hiding the markers
+		"2024/2024-01-16.md": "```go``` fences\n\n:::private\nSalary negotiation\n:::\n",
+	}
+	for _, name := range []string{"private.png", "secret.png", "public.png"} {
+		files["2024/img/"+name] = "png"
+	}
+	b, _ := newTestBuilder(t, files)
+	secrets := []string{"confidential", "Salary", "Doctor", "private.png", "secret.png"}
+
+	outputDir := t.TempDir()
+	if err := b.Build(outputDir); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	printDir := t.TempDir()
+	if err := b.BuildPrint(printDir, util.DateRange{}); err != nil {
+		t.Fatalf("BuildPrint() error = %v", err)
+	}
+
+	for _, dir := range []string{outputDir, printDir} {
+		var all strings.Builder
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			all.WriteString(path + "\n")
+			data, err := os.ReadFile(path)
+			all.Write(data)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range secrets {
+			if strings.Contains(all.String(), secret) {
+				t.Errorf("%q found in output directory", secret)
+			}
+		}
+		for _, public := range []string{"Public note", "More public", "public.png"} {
+			if !strings.Contains(all.String(), public) {
+				t.Errorf("%q missing from output directory", public)
+			}
+		}
+		if strings.Contains(all.String(), `id="2024-01-14"`) {
+			t.Error("private entry listed in output")
+		}
+	}
+}
+
+func TestEntryRenderer_ShowPrivate(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfg := &config.Config{}
+	cfg.SetDefaults()
+	r := newEntryRenderer(cfg, jnal.NewJournal(cfg), t.TempDir(), true)
+
+	entry, err := r.convert([]byte("---\nprivate: true\n---\nPublic\n\n:::private\nSecret\n:::\n"), "", "2024-01-15")
+	if err != nil {
+		t.Fatalf("convert() error = %v", err)
+	}
+	if !entry.Private {
+		t.Error("Private = false, want true")
+	}
+	want := "<p>Public</p>\n<div class=\"private-block\">\n<p>Secret</p>\n</div>\n"
+	if entry.HTML != want {
+		t.Errorf("HTML = %q, want %q", entry.HTML, want)
+	}
+	if entry.Words != 2 {
+		t.Errorf("Words = %d, want 2", entry.Words)
+	}
+}
+
+func TestBuilder_Build_DraftsAndFuture(t *testing.T) {
+	next := util.Today().AddDate(0, 0, 7)
+	files := map[string]string{
+		"2024/2024-01-13.md": "---\nMorning notes\n\n---\nEvening notes\n",
+		"2024/2024-01-14.md": "Published entry\n",
+		"2024/2024-01-15.md": "---\ndraft: true\n---\nDraft entry\n",
+		"2024/2024-01-16.md": "---\npublish_date: " + util.Format(next) + "\n---\nScheduled entry\n",
+		next.Format("2006") + "/" + util.Format(next) + ".md": "Prepared entry\n",
+	}
+
+	tests := []struct {
+		name    string
+		drafts  bool
+		future  bool
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "default",
+			want:    []string{"Published entry", "Prepared entry", "Morning notes", "Evening notes"},
+			notWant: []string{"Draft entry", "Scheduled entry"},
+		},
+		{
+			name:    "drafts",
+			drafts:  true,
+			want:    []string{"Published entry", "Prepared entry", "Draft entry", `class="badge draft"`},
+			notWant: []string{"Scheduled entry"},
+		},
+		{
+			name:    "future",
+			future:  true,
+			want:    []string{"Published entry", "Prepared entry", "Scheduled entry", "scheduled " + util.Format(next)},
+			notWant: []string{"Draft entry"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := newTestBuilder(t, files)
+			b.cfg.Build.Drafts = tt.drafts
+			b.cfg.Build.Future = tt.future
+
+			outputDir := t.TempDir()
+			if err := b.Build(outputDir); err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			index, err := os.ReadFile(filepath.Join(outputDir, "index.html"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(string(index), s) {
+					t.Errorf("index.html missing %q", s)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(string(index), s) {
+					t.Errorf("index.html contains %q", s)
+				}
+			}
+		})
+	}
+}
+
+func TestEntryRenderer_RenderAll(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	baseDir := t.TempDir()
+
+	var entries jnal.Entries
+	for day := 1; day <= 6; day++ {
+		date := time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)
+		path := filepath.Join(baseDir, util.Format(date)+".md")
+		// Every third entry is missing and fails to read
+		if day%3 != 0 {
+			mustWrite(t, path, fmt.Sprintf("Entry %d\n", day))
+		}
+		entries = append(entries, jnal.Entry{Path: path, Date: date})
+	}
+
+	cfg := &config.Config{Common: config.CommonConfig{BaseDirectory: baseDir}}
+	cfg.SetDefaults()
+	r := newEntryRenderer(cfg, jnal.NewJournal(cfg), baseDir, false)
+
+	for _, jobs := range []int{0, 1, 4, len(entries) + 5} {
+		t.Run(fmt.Sprintf("jobs=%d", jobs), func(t *testing.T) {
+			results := r.renderAll(entries, jobs)
+			if len(results) != len(entries) {
+				t.Fatalf("renderAll() returned %d results, want %d", len(results), len(entries))
+			}
+			for i, result := range results {
+				day := i + 1
+				if day%3 == 0 {
+					if result != nil {
+						t.Errorf("result %d = %+v, want nil for missing entry", i, result)
+					}
+					continue
+				}
+				want := fmt.Sprintf("<p>Entry %d</p>\n", day)
+				if result == nil || result.HTML != want {
+					t.Errorf("result %d = %+v, want HTML %q", i, result, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuilder_Build_UniqueIDs(t *testing.T) {
+	b, _ := newTestBuilder(t, map[string]string{
+		"2024/2024-01-15.md":   "# Retro notes\n\n## 2\n\nFirst\n",
+		"2024/2024-01-15-2.md": "# Retro notes\n\nSecond\n",
+	})
+
+	outputDir := t.TempDir()
+	if err := b.Build(outputDir); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	index, err := os.ReadFile(filepath.Join(outputDir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The heading "2" doesn't take the ID of the second entry of the day
+	for _, id := range []string{"2024-01-15--retro-notes", "2024-01-15--2", "2024-01-15-2", "2024-01-15-2--retro-notes"} {
+		if !strings.Contains(string(index), `id="`+id+`"`) {
+			t.Errorf("index.html missing id %q", id)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, m := range regexp.MustCompile(`\sid="([^"]*)"`).FindAllStringSubmatch(string(index), -1) {
+		if seen[m[1]] {
+			t.Errorf("duplicate id %q in index.html", m[1])
+		}
+		seen[m[1]] = true
+	}
+}
+
+func TestBuilder_Build_AnchorsResolve(t *testing.T) {
+	b, _ := newTestBuilder(t, map[string]string{
+		"2024/2024-01-14-plans.md": "# Plans\n\nFirst\n",
+		"2024/2024-01-15-notes.md": "# Retro notes\n\n## Next\n\nSecond\n",
+		"2024/2024-01-15.md":       "# 2\n\nThird\n",
+	})
+
+	siteDir, printDir := t.TempDir(), t.TempDir()
+	if err := b.Build(siteDir); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if err := b.BuildPrint(printDir, util.DateRange{}); err != nil {
+		t.Fatalf("BuildPrint() error = %v", err)
+	}
+
+	for _, name := range []string{filepath.Join(siteDir, "index.html"), filepath.Join(printDir, "index.html")} {
+		page, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make(map[string]bool)
+		for _, m := range regexp.MustCompile(`\sid="([^"]*)"`).FindAllStringSubmatch(string(page), -1) {
+			ids[m[1]] = true
+		}
+		hrefs := regexp.MustCompile(`href="#([^"]*)"`).FindAllStringSubmatch(string(page), -1)
+		if len(hrefs) == 0 {
+			t.Errorf("%s has no anchor links", name)
+		}
+		for _, m := range hrefs {
+			if !ids[m[1]] {
+				t.Errorf("%s links to missing anchor %q", name, m[1])
+			}
+		}
+	}
+}
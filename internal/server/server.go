@@ -1,16 +1,12 @@
 package server
 
 import (
-	"bytes"
 	"context"
-	"embed"
 	"fmt"
 	"html/template"
-	"io"
 	"net/http"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -18,17 +14,49 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/longkey1/jnal/internal/config"
 	"github.com/longkey1/jnal/internal/jnal"
-	"github.com/yuin/goldmark"
-	"github.com/yuin/goldmark/extension"
-	"github.com/yuin/goldmark/renderer"
-	"github.com/yuin/goldmark/renderer/html"
+	"github.com/longkey1/jnal/internal/util"
 )
 
-//go:embed templates/*.html
-var templatesFS embed.FS
-
-// DefaultCSS is the default stylesheet
+// DefaultCSS is the default stylesheet. Its colors follow the system light or dark
+// color scheme on screen, unless data-theme on the root element selects one.
 const DefaultCSS = `
+:root {
+    --bg: #fafafa;
+    --fg: #333;
+    --muted: #666;
+    --link: #007acc;
+    --border: #ddd;
+    --card-bg: white;
+    --card-shadow: 0 1px 3px rgba(0,0,0,0.1);
+    --code-bg: #f4f4f4;
+    color-scheme: light;
+}
+@media screen and (prefers-color-scheme: dark) {
+    :root:not([data-theme="light"]) {
+        --bg: #16181c;
+        --fg: #d6d6d6;
+        --muted: #9a9a9a;
+        --link: #4fa8e8;
+        --border: #3a3d42;
+        --card-bg: #1f2227;
+        --card-shadow: 0 1px 3px rgba(0,0,0,0.5);
+        --code-bg: #2a2d33;
+        color-scheme: dark;
+    }
+}
+@media screen {
+    :root[data-theme="dark"] {
+        --bg: #16181c;
+        --fg: #d6d6d6;
+        --muted: #9a9a9a;
+        --link: #4fa8e8;
+        --border: #3a3d42;
+        --card-bg: #1f2227;
+        --card-shadow: 0 1px 3px rgba(0,0,0,0.5);
+        --code-bg: #2a2d33;
+        color-scheme: dark;
+    }
+}
 * { box-sizing: border-box; }
 body {
     font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
@@ -36,45 +64,47 @@ body {
     max-width: 800px;
     margin: 0 auto;
     padding: 20px;
-    background-color: #fafafa;
-    color: #333;
+    background-color: var(--bg);
+    color: var(--fg);
 }
-h1 { border-bottom: 2px solid #333; padding-bottom: 10px; }
-a { color: #007acc; text-decoration: none; }
+h1 { border-bottom: 2px solid var(--fg); padding-bottom: 10px; }
+a { color: var(--link); text-decoration: none; }
 a:hover { text-decoration: underline; }
-nav { border-bottom: 1px solid #ddd; margin-bottom: 20px; }
+nav { background: var(--bg); border-bottom: 1px solid var(--border); margin-bottom: 20px; }
 h2 { font-size: 1.5em; margin: 1.5em 0 0.5em; }
 h3 { font-size: 1.35em; margin: 1.2em 0 0.4em; }
 article {
-    background: white;
+    background: var(--card-bg);
     padding: 20px;
     border-radius: 5px;
-    box-shadow: 0 1px 3px rgba(0,0,0,0.1);
+    box-shadow: var(--card-shadow);
     margin-bottom: 20px;
 }
 article h4 { margin-top: 0; font-size: 1.2em; }
 .content h5 { font-size: 1.1em; margin: 1.2em 0 0.6em; }
 .content h6 { font-size: 1em; margin: 1em 0 0.5em; }
-article pre, article code { background: #f4f4f4; }
+article pre, article code { background: var(--code-bg); }
 article pre { padding: 15px; border-radius: 5px; overflow-x: auto; }
 article code { padding: 2px 6px; border-radius: 3px; }
 article pre code { background: none; padding: 0; }
-article blockquote { border-left: 4px solid #ddd; margin: 0; padding-left: 20px; color: #666; }
+article blockquote { border-left: 4px solid var(--border); margin: 0; padding-left: 20px; color: var(--muted); }
 `
 
+// reloadDebounce is how long the watcher waits for further changes before reloading
+const reloadDebounce = 100 * time.Millisecond
+
 // Server represents the journal preview server
 type Server struct {
-	cfg             *config.Config
-	journal         *jnal.Journal
-	baseDir         string
-	css             string
-	liveReload      bool
-	linkTargetBlank bool
+	cfg     *config.Config
+	journal *jnal.Journal
+	baseDir string
+	stylesheets
+	liveReload bool
+	renderer   *entryRenderer
 
 	mu      sync.RWMutex
 	entries jnal.Entries
 	tmpl    *template.Template
-	md      goldmark.Markdown
 
 	// SSE clients for live reload
 	sseClients   map[chan struct{}]struct{}
@@ -83,72 +113,35 @@ type Server struct {
 
 // New creates a new Server instance
 func New(cfg *config.Config, jnl *jnal.Journal, baseDir string, liveReload bool) (*Server, error) {
-	tmpl, err := template.ParseFS(templatesFS, "templates/*.html")
+	tmpl, err := parseTemplates(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("parsing templates: %w", err)
 	}
 
 	// Load CSS
-	css, err := loadCSS(cfg.Build.CSS)
+	styles, err := loadStylesheets(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("loading css: %w", err)
 	}
 
-	// Configure goldmark
-	var opts []goldmark.Option
-	var rendererOpts []renderer.Option
-	if cfg.Build.GetHardWraps() {
-		rendererOpts = append(rendererOpts, html.WithHardWraps())
-	}
-	if len(rendererOpts) > 0 {
-		opts = append(opts, goldmark.WithRendererOptions(rendererOpts...))
-	}
-	if cfg.Build.GetLinkify() {
-		opts = append(opts, goldmark.WithExtensions(extension.Linkify))
-	}
-	md := goldmark.New(opts...)
-
 	return &Server{
-		cfg:             cfg,
-		journal:         jnl,
-		baseDir:         baseDir,
-		css:             css,
-		liveReload:      liveReload,
-		linkTargetBlank: cfg.Build.GetLinkTargetBlank(),
-		tmpl:            tmpl,
-		md:              md,
-		sseClients:      make(map[chan struct{}]struct{}),
+		cfg:         cfg,
+		journal:     jnl,
+		baseDir:     baseDir,
+		stylesheets: styles,
+		liveReload:  liveReload,
+		renderer:    newEntryRenderer(cfg, jnl, baseDir, true),
+		tmpl:        tmpl,
+		sseClients:  make(map[chan struct{}]struct{}),
 	}, nil
 }
 
-// loadCSS loads CSS from URL or returns the string as-is
-func loadCSS(css string) (string, error) {
-	if css == "" {
-		return DefaultCSS, nil
-	}
-
-	// Check if it's a URL
-	if strings.HasPrefix(css, "http://") || strings.HasPrefix(css, "https://") {
-		resp, err := http.Get(css)
-		if err != nil {
-			return "", fmt.Errorf("fetching CSS from %s: %w", css, err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			return "", fmt.Errorf("fetching CSS from %s: status %d", css, resp.StatusCode)
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return "", fmt.Errorf("reading CSS response: %w", err)
-		}
-
-		return string(body), nil
+// pages returns the links to the extra pages of the preview server
+func (s *Server) pages() []PageLink {
+	return []PageLink{
+		{Title: "On this day", URL: "/onthisday"},
+		{Title: "Stats", URL: "/stats"},
 	}
-
-	// Return as-is (inline CSS)
-	return css, nil
 }
 
 // Start starts the server
@@ -164,6 +157,8 @@ func (s *Server) Start(ctx context.Context) error {
 	// Setup HTTP handlers
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/stats", s.handleStats)
+	mux.HandleFunc("/onthisday", s.handleOnThisDay)
 	if s.liveReload {
 		mux.HandleFunc("/events", s.handleSSE)
 	}
@@ -199,20 +194,16 @@ func (s *Server) reloadEntries() error {
 	}
 
 	// Sort entries based on configuration
-	switch s.cfg.Build.Sort {
-	case config.SortAsc:
-		entries.SortByDateAsc()
-	default:
-		entries.SortByDateDesc()
-	}
+	sortEntries(entries, s.cfg.Build.Sort)
 
 	// Load content for each entry
-	for i := range entries {
-		content, err := s.loadEntryContent(entries[i].Path)
-		if err != nil {
-			continue
+	for i, rendered := range s.renderer.renderAll(entries, s.cfg.Build.GetJobs()) {
+		if rendered != nil {
+			entries[i].Content = rendered.HTML
+			entries[i].Words = rendered.Words
+			entries[i].Private = rendered.Private
+			entries[i].Publish = rendered.Publish
 		}
-		entries[i].Content = content
 	}
 
 	s.mu.Lock()
@@ -222,57 +213,17 @@ func (s *Server) reloadEntries() error {
 	return nil
 }
 
-// loadEntryContent loads and converts markdown content to HTML
-func (s *Server) loadEntryContent(path string) (string, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return "", err
-	}
-
-	var buf bytes.Buffer
-	if err := s.md.Convert(data, &buf); err != nil {
-		return "", err
-	}
-
-	result := buf.String()
-
-	if s.linkTargetBlank {
-		result = addTargetBlankToLinks(result)
-	}
-
-	shift := s.cfg.Build.GetHeadingShift()
-	if shift > 0 {
-		result = shiftHeadings(result, shift)
-	}
-	return result, nil
-}
-
-// shiftHeadings shifts HTML heading levels by the specified amount
-// H1 becomes H1+shift, H2 becomes H2+shift, etc.
-// Headings are clamped to H6 maximum
-func shiftHeadings(html string, shift int) string {
-	// Process from h6 to h1 to avoid double replacement
-	for level := 6; level >= 1; level-- {
-		newLevel := level + shift
-		if newLevel > 6 {
-			newLevel = 6
-		}
-
-		// Use regex to handle attributes in opening tags
-		re := regexp.MustCompile(fmt.Sprintf(`<h%d(\s|>)`, level))
-		html = re.ReplaceAllString(html, fmt.Sprintf("<h%d$1", newLevel))
-		html = strings.ReplaceAll(html, fmt.Sprintf("</h%d>", level), fmt.Sprintf("</h%d>", newLevel))
+// sortEntries sorts entries in the given order
+func sortEntries(entries jnal.Entries, order string) {
+	switch order {
+	case config.SortAsc:
+		entries.SortByDateAsc()
+	default:
+		entries.SortByDateDesc()
 	}
-	return html
-}
-
-// addTargetBlankToLinks adds target="_blank" and rel="noopener noreferrer" to all links
-func addTargetBlankToLinks(html string) string {
-	re := regexp.MustCompile(`<a\s+href=`)
-	return re.ReplaceAllString(html, `<a target="_blank" rel="noopener noreferrer" href=`)
 }
 
-// watchFiles watches for file changes and reloads entries
+// watchFiles watches for file changes and reloads the changed entries
 func (s *Server) watchFiles(ctx context.Context) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -282,39 +233,68 @@ func (s *Server) watchFiles(ctx context.Context) {
 	defer watcher.Close()
 
 	// Watch base directory and all subdirectories
-	err = filepath.Walk(s.baseDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			if err := watcher.Add(path); err != nil {
-				fmt.Printf("Error watching directory %s: %v\n", path, err)
-			}
-		}
-		return nil
-	})
-	if err != nil {
+	if _, err := watchDir(watcher, s.baseDir); err != nil {
 		fmt.Printf("Error walking directory: %v\n", err)
 		return
 	}
 
+	// Editors emit bursts of events for a single save, so changes are
+	// collected and applied once the burst is over
+	pending := make(map[string]struct{})
+	debounce := time.NewTimer(reloadDebounce)
+	debounce.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
+			debounce.Stop()
 			return
 		case event, ok := <-watcher.Events:
 			if !ok {
 				return
 			}
-			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove) != 0 {
-				if filepath.Ext(event.Name) == ".md" {
-					fmt.Printf("File changed: %s, reloading...\n", filepath.Base(event.Name))
-					if err := s.reloadEntries(); err != nil {
-						fmt.Printf("Error reloading entries: %v\n", err)
+
+			changed := false
+			switch {
+			case event.Has(fsnotify.Create):
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// Watch new directories and pick up entries moved in with them
+					files, err := watchDir(watcher, event.Name)
+					if err != nil {
+						fmt.Printf("Error watching directory %s: %v\n", event.Name, err)
+					}
+					for _, f := range files {
+						pending[f] = struct{}{}
 					}
-					s.notifyClients()
+					changed = len(files) > 0
+				} else if jnal.IsEntryFile(event.Name) {
+					pending[event.Name] = struct{}{}
+					changed = true
+				}
+			case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+				// The path may be a file or a whole directory, which no longer
+				// exists, so it is checked against the loaded entries
+				if s.hasEntriesUnder(event.Name) {
+					pending[event.Name] = struct{}{}
+					changed = true
+				}
+			case event.Has(fsnotify.Write):
+				if jnal.IsEntryFile(event.Name) {
+					pending[event.Name] = struct{}{}
+					changed = true
 				}
 			}
+
+			if changed {
+				debounce.Reset(reloadDebounce)
+			}
+		case <-debounce.C:
+			for path := range pending {
+				fmt.Printf("File changed: %s, reloading...\n", filepath.Base(path))
+			}
+			s.applyChanges(pending)
+			pending = make(map[string]struct{})
+			s.notifyClients()
 		case err, ok := <-watcher.Errors:
 			if !ok {
 				return
@@ -324,6 +304,95 @@ func (s *Server) watchFiles(ctx context.Context) {
 	}
 }
 
+// watchDir adds the directory and all its subdirectories to the watcher
+// and returns the Markdown files found in them
+func watchDir(watcher *fsnotify.Watcher, dir string) ([]string, error) {
+	var files []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if err := watcher.Add(path); err != nil {
+				fmt.Printf("Error watching directory %s: %v\n", path, err)
+			}
+		} else if jnal.IsEntryFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+// applyChanges reloads the entries for the changed paths only.
+// Paths that no longer exist (removed or renamed files and directories) are dropped.
+func (s *Server) applyChanges(paths map[string]struct{}) {
+	updated := make(map[string]jnal.Entry)
+	var removed []string
+
+	for path := range paths {
+		entry, err := s.journal.GetEntry(path)
+		if err != nil {
+			removed = append(removed, path)
+			continue
+		}
+
+		rendered, err := s.renderer.render(path)
+		if err != nil {
+			fmt.Printf("Error loading entry %s: %v\n", path, err)
+			continue
+		}
+		entry.Content = rendered.HTML
+		entry.Words = rendered.Words
+		entry.Private = rendered.Private
+		entry.Publish = rendered.Publish
+		updated[path] = entry
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Build a new slice, since handlers may still hold the current one
+	entries := make(jnal.Entries, 0, len(s.entries)+len(updated))
+	for _, e := range s.entries {
+		if _, ok := updated[e.Path]; ok || isUnderAny(e.Path, removed) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	for _, e := range updated {
+		entries = append(entries, e)
+	}
+
+	sortEntries(entries, s.cfg.Build.Sort)
+	s.entries = entries
+}
+
+// hasEntriesUnder reports whether path is a loaded entry or a directory containing one
+func (s *Server) hasEntriesUnder(path string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, e := range s.entries {
+		if isUnderAny(e.Path, []string{path}) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnderAny reports whether path is one of dirs or located under one of them
+func isUnderAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
 // handleSSE handles Server-Sent Events for live reload
 func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/event-stream")
@@ -383,7 +452,7 @@ func (s *Server) notifyClients() {
 // handleIndex handles the index page
 func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path != "/" {
-		http.NotFound(w, r)
+		s.handleAsset(w, r)
 		return
 	}
 
@@ -394,11 +463,15 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 	templateEntries, yearNavs := convertToTemplateEntries(entries)
 
 	data := IndexData{
-		Title:      s.cfg.Build.Title,
-		Entries:    templateEntries,
-		YearNavs:   yearNavs,
-		CSS:        template.CSS(s.css),
-		LiveReload: s.liveReload,
+		Title:          s.cfg.Build.Title,
+		Entries:        templateEntries,
+		YearNavs:       yearNavs,
+		CSS:            template.CSS(s.css),
+		CSSDark:        template.CSS(s.cssDark),
+		DarkModeToggle: s.darkModeToggle,
+		Pages:          s.pages(),
+		LiveReload:     s.liveReload,
+		PrivateToggle:  true,
 	}
 
 	if err := s.tmpl.ExecuteTemplate(w, "index.html", data); err != nil {
@@ -410,6 +483,9 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 func convertToTemplateEntries(entries jnal.Entries) ([]TemplateEntry, []YearNav) {
 	templateEntries := make([]TemplateEntry, len(entries))
 	yearNavs := []YearNav{}
+	today := util.Today()
+	yearIndexes := make([]int, len(entries))
+	activity := newDayActivity(entries)
 	lastYear := ""
 	lastMonth := ""
 
@@ -422,96 +498,144 @@ func convertToTemplateEntries(entries jnal.Entries) ([]TemplateEntry, []YearNav)
 		showMonth := yearMonth != lastMonth
 
 		if showYear {
-			yearNavs = append(yearNavs, YearNav{Year: year, Months: []string{month}})
+			yearNavs = append(yearNavs, YearNav{
+				Year:    year,
+				Months:  []string{month},
+				Heatmap: activity.yearHeatmap(e.Date.Year()),
+			})
 			lastYear = year
 		} else if showMonth {
 			yearNavs[len(yearNavs)-1].Months = append(yearNavs[len(yearNavs)-1].Months, month)
 		}
 		lastMonth = yearMonth
+		yearIndexes[i] = len(yearNavs) - 1
 
 		templateEntries[i] = TemplateEntry{
+			ID:         entryAnchor(e.Path),
 			Date:       e.Date,
 			Content:    template.HTML(e.Content),
+			Words:      e.Words,
 			ShowYear:   showYear,
 			YearLabel:  year,
 			ShowMonth:  showMonth,
 			MonthLabel: yearMonth,
+			Private:    e.Private,
+			Draft:      e.Publish.Draft,
+			Scheduled:  scheduledDate(e, today),
+		}
+		if showMonth {
+			templateEntries[i].Calendar = activity.monthCalendar(e.Date)
+		}
+	}
+
+	// Link year headings to their navigation once all months are known
+	for i := range templateEntries {
+		if templateEntries[i].ShowYear {
+			templateEntries[i].Year = &yearNavs[yearIndexes[i]]
 		}
 	}
 
 	return templateEntries, yearNavs
 }
 
-// TemplateEntry represents an entry for template rendering
+// TemplateEntry represents an entry for template rendering.
+// It is part of the data available to custom templates.
 type TemplateEntry struct {
-	Date       time.Time
-	Content    template.HTML
-	ShowYear   bool
-	YearLabel  string
+	// ID is the anchor of the entry, its slugified file name from the date on
+	// (2024-01-15, 2024-01-15-2, 2024-01-15-notes)
+	ID string
+	// Date is the entry date at midnight UTC
+	Date time.Time
+	// Content is the rendered HTML of the entry, without front matter
+	Content template.HTML
+	// Words is the word count of the entry
+	Words int
+	// ShowYear is set on the first entry of a year, labeled YearLabel (2024)
+	ShowYear  bool
+	YearLabel string
+	// ShowMonth is set on the first entry of a month, labeled MonthLabel (2024-01)
 	ShowMonth  bool
 	MonthLabel string
+	// Private and Draft are set from the front matter; only the preview server shows such entries
+	Private bool
+	Draft   bool
+	// Scheduled is the publish date of entries published after today
+	Scheduled string
+	// Year is set on the first entry of a year
+	Year *YearNav
+	// Calendar is set on the first entry of a month
+	Calendar *MonthCalendar
 }
 
-// YearNav represents navigation for a year
+// YearNav represents navigation for a year.
+// It is part of the data available to custom templates.
 type YearNav struct {
-	Year   string
+	// Year is the year label (2024), used as the id of the year heading
+	Year string
+	// Months are the months with entries (01 to 12) in entry order
 	Months []string
+	// Heatmap is the activity of the year by week
+	Heatmap []CalendarWeek
+}
+
+// PageLink represents a link to another page in the navigation
+type PageLink struct {
+	Title string
+	URL   string
 }
 
-// IndexData represents data for the index template
+// IndexData represents data for the index template.
+// It is the data available to a custom index.html template.
 type IndexData struct {
-	Title      string
-	Entries    []TemplateEntry
-	YearNavs   []YearNav
-	CSS        template.CSS
+	// Title is build.title
+	Title string
+	// Entries are the entries in build.sort order
+	Entries []TemplateEntry
+	// YearNavs are the years with entries in entry order
+	YearNavs []YearNav
+	// CSS is the stylesheet from build.css, or the default one
+	CSS template.CSS
+	// CSSDark is the stylesheet from build.css_dark, replacing CSS in the dark color scheme
+	CSSDark template.CSS
+	// DarkModeToggle shows the light/dark switch
+	DarkModeToggle bool
+	// Pages are the extra pages of the preview server, empty in builds
+	Pages []PageLink
+	// LiveReload is set when the preview server reloads the page on changes
 	LiveReload bool
+	// PrivateToggle shows the control revealing private entries and blocks
+	PrivateToggle bool
 }
 
 // Builder generates static HTML files
 type Builder struct {
-	cfg             *config.Config
-	journal         *jnal.Journal
-	baseDir         string
-	css             string
-	linkTargetBlank bool
-	tmpl            *template.Template
-	md              goldmark.Markdown
+	cfg     *config.Config
+	journal *jnal.Journal
+	baseDir string
+	stylesheets
+	renderer *entryRenderer
+	tmpl     *template.Template
 }
 
 // NewBuilder creates a new Builder instance
 func NewBuilder(cfg *config.Config, jnl *jnal.Journal, baseDir string) (*Builder, error) {
-	tmpl, err := template.ParseFS(templatesFS, "templates/*.html")
+	tmpl, err := parseTemplates(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("parsing templates: %w", err)
 	}
 
-	css, err := loadCSS(cfg.Build.CSS)
+	styles, err := loadStylesheets(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("loading css: %w", err)
 	}
 
-	// Configure goldmark
-	var opts []goldmark.Option
-	var rendererOpts []renderer.Option
-	if cfg.Build.GetHardWraps() {
-		rendererOpts = append(rendererOpts, html.WithHardWraps())
-	}
-	if len(rendererOpts) > 0 {
-		opts = append(opts, goldmark.WithRendererOptions(rendererOpts...))
-	}
-	if cfg.Build.GetLinkify() {
-		opts = append(opts, goldmark.WithExtensions(extension.Linkify))
-	}
-	md := goldmark.New(opts...)
-
 	return &Builder{
-		cfg:             cfg,
-		journal:         jnl,
-		baseDir:         baseDir,
-		css:             css,
-		linkTargetBlank: cfg.Build.GetLinkTargetBlank(),
-		tmpl:            tmpl,
-		md:              md,
+		cfg:         cfg,
+		journal:     jnl,
+		baseDir:     baseDir,
+		stylesheets: styles,
+		renderer:    newEntryRenderer(cfg, jnl, baseDir, false),
+		tmpl:        tmpl,
 	}, nil
 }
 
@@ -529,20 +653,12 @@ func (b *Builder) Build(outputDir string) error {
 	}
 
 	// Sort entries
-	switch b.cfg.Build.Sort {
-	case config.SortAsc:
-		entries.SortByDateAsc()
-	default:
-		entries.SortByDateDesc()
-	}
+	sortEntries(entries, b.cfg.Build.Sort)
 
-	// Load content for each entry
-	for i := range entries {
-		content, err := b.loadEntryContent(entries[i].Path)
-		if err != nil {
-			continue
-		}
-		entries[i].Content = content
+	// Render entries and copy referenced assets (images, attachments)
+	entries, assets := b.renderEntries(entries)
+	if err := copyAssets(b.baseDir, outputDir, assets); err != nil {
+		return fmt.Errorf("copying assets: %w", err)
 	}
 
 	// Convert to template entries
@@ -550,47 +666,70 @@ func (b *Builder) Build(outputDir string) error {
 
 	// Generate index.html
 	indexData := IndexData{
-		Title:    b.cfg.Build.Title,
-		Entries:  templateEntries,
-		YearNavs: yearNavs,
-		CSS:      template.CSS(b.css),
+		Title:          b.cfg.Build.Title,
+		Entries:        templateEntries,
+		YearNavs:       yearNavs,
+		CSS:            template.CSS(b.css),
+		CSSDark:        template.CSS(b.cssDark),
+		DarkModeToggle: b.darkModeToggle,
 	}
 
-	indexPath := filepath.Join(outputDir, "index.html")
-	indexFile, err := os.Create(indexPath)
-	if err != nil {
-		return fmt.Errorf("creating index.html: %w", err)
-	}
-	defer indexFile.Close()
+	return b.writePage(outputDir, "index.html", indexData)
+}
 
-	if err := b.tmpl.ExecuteTemplate(indexFile, "index.html", indexData); err != nil {
-		return fmt.Errorf("executing index template: %w", err)
+// renderEntries renders the content of each entry and returns the entries without the
+// private and unpublished ones, along with the assets they reference
+func (b *Builder) renderEntries(entries jnal.Entries) (jnal.Entries, map[string]struct{}) {
+	public := make(jnal.Entries, 0, len(entries))
+	assets := make(map[string]struct{})
+	for i, rendered := range b.renderer.renderAll(entries, b.cfg.Build.GetJobs()) {
+		if rendered == nil {
+			public = append(public, entries[i])
+			continue
+		}
+		if rendered.Private || !b.published(rendered.Publish) {
+			continue
+		}
+		entries[i].Content = rendered.HTML
+		entries[i].Words = rendered.Words
+		entries[i].Publish = rendered.Publish
+		public = append(public, entries[i])
+		for _, asset := range rendered.Assets {
+			assets[asset] = struct{}{}
+		}
 	}
-
-	return nil
+	return public, assets
 }
 
-// loadEntryContent loads and converts markdown content to HTML
-func (b *Builder) loadEntryContent(path string) (string, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return "", err
+// published reports whether an entry with the publishing metadata is built,
+// given the drafts and future settings
+func (b *Builder) published(publish jnal.PublishStatus) bool {
+	if publish.Draft && !b.cfg.Build.Drafts {
+		return false
 	}
+	return b.cfg.Build.Future || !publish.IsFuture(util.Today())
+}
 
-	var buf bytes.Buffer
-	if err := b.md.Convert(data, &buf); err != nil {
-		return "", err
+// scheduledDate returns the publish_date of an entry published after today, or ""
+func scheduledDate(e jnal.Entry, today time.Time) string {
+	if !e.Publish.IsFuture(today) {
+		return ""
 	}
+	return util.Format(e.Publish.PublishDate)
+}
 
-	result := buf.String()
-
-	if b.linkTargetBlank {
-		result = addTargetBlankToLinks(result)
+// writePage executes the template into index.html in the output directory
+func (b *Builder) writePage(outputDir, name string, data any) error {
+	indexPath := filepath.Join(outputDir, "index.html")
+	indexFile, err := os.Create(indexPath)
+	if err != nil {
+		return fmt.Errorf("creating index.html: %w", err)
 	}
+	defer indexFile.Close()
 
-	shift := b.cfg.Build.GetHeadingShift()
-	if shift > 0 {
-		result = shiftHeadings(result, shift)
+	if err := b.tmpl.ExecuteTemplate(indexFile, name, data); err != nil {
+		return fmt.Errorf("executing %s template: %w", strings.TrimSuffix(name, ".html"), err)
 	}
-	return result, nil
+
+	return indexFile.Close()
 }
@@ -0,0 +1,263 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/jnal"
+)
+
+func TestServer_ApplyChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		// change modifies the files in baseDir and returns the changed paths
+		change      func(t *testing.T, baseDir string) []string
+		wantEntries []string
+		wantContent map[string]string
+	}{
+		{
+			name: "edited entry",
+			change: func(t *testing.T, baseDir string) []string {
+				path := filepath.Join(baseDir, "2024", "2024-01-15.md")
+				mustWrite(t, path, "Edited entry\n")
+				return []string{path}
+			},
+			wantEntries: []string{"2024/2024-01-15.md", "2024/2024-01-14.md", "2023/2023-12-31.md"},
+			wantContent: map[string]string{"2024/2024-01-15.md": "Edited entry"},
+		},
+		{
+			name: "new entry",
+			change: func(t *testing.T, baseDir string) []string {
+				path := filepath.Join(baseDir, "2024", "2024-01-16.md")
+				mustWrite(t, path, "New entry\n")
+				return []string{path}
+			},
+			wantEntries: []string{"2024/2024-01-16.md", "2024/2024-01-15.md", "2024/2024-01-14.md", "2023/2023-12-31.md"},
+			wantContent: map[string]string{"2024/2024-01-16.md": "New entry"},
+		},
+		{
+			name: "deleted file",
+			change: func(t *testing.T, baseDir string) []string {
+				path := filepath.Join(baseDir, "2024", "2024-01-14.md")
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+				return []string{path}
+			},
+			wantEntries: []string{"2024/2024-01-15.md", "2023/2023-12-31.md"},
+		},
+		{
+			name: "deleted directory",
+			change: func(t *testing.T, baseDir string) []string {
+				dir := filepath.Join(baseDir, "2024")
+				if err := os.RemoveAll(dir); err != nil {
+					t.Fatal(err)
+				}
+				return []string{dir}
+			},
+			wantEntries: []string{"2023/2023-12-31.md"},
+		},
+		{
+			name: "non-entry file",
+			change: func(t *testing.T, baseDir string) []string {
+				path := filepath.Join(baseDir, "2024", "notes.txt")
+				mustWrite(t, path, "Not an entry\n")
+				return []string{path}
+			},
+			wantEntries: []string{"2024/2024-01-15.md", "2024/2024-01-14.md", "2023/2023-12-31.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CACHE_HOME", t.TempDir())
+			baseDir := t.TempDir()
+			mustWrite(t, filepath.Join(baseDir, "2023", "2023-12-31.md"), "Last entry of 2023\n")
+			mustWrite(t, filepath.Join(baseDir, "2024", "2024-01-14.md"), "First entry\n")
+			mustWrite(t, filepath.Join(baseDir, "2024", "2024-01-15.md"), "Second entry\n")
+
+			cfg := &config.Config{Common: config.CommonConfig{BaseDirectory: baseDir}}
+			cfg.SetDefaults()
+			s, err := New(cfg, jnal.NewJournal(cfg), baseDir, false)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if err := s.reloadEntries(); err != nil {
+				t.Fatalf("reloadEntries() error = %v", err)
+			}
+
+			paths := make(map[string]struct{})
+			for _, path := range tt.change(t, baseDir) {
+				paths[path] = struct{}{}
+			}
+			s.applyChanges(paths)
+
+			var got []string
+			content := make(map[string]string)
+			for _, e := range s.entries {
+				rel, err := filepath.Rel(baseDir, e.Path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, filepath.ToSlash(rel))
+				content[filepath.ToSlash(rel)] = e.Content
+			}
+			if !slices.Equal(got, tt.wantEntries) {
+				t.Errorf("entries = %v, want %v", got, tt.wantEntries)
+			}
+			for path, want := range tt.wantContent {
+				if !strings.Contains(content[path], want) {
+					t.Errorf("content of %s = %q, want %q", path, content[path], want)
+				}
+			}
+		})
+	}
+}
+
+func TestWatchDir(t *testing.T) {
+	baseDir := t.TempDir()
+	mustWrite(t, filepath.Join(baseDir, "2024-01-13.md"), "Top level\n")
+	mustWrite(t, filepath.Join(baseDir, "2024", "01", "2024-01-14.md"), "Nested\n")
+	mustWrite(t, filepath.Join(baseDir, "2024", "01", "2024-01-15.md.age"), "Encrypted\n")
+	mustWrite(t, filepath.Join(baseDir, "2024", "01", "attachments", "photo.png"), "image")
+	mustWrite(t, filepath.Join(baseDir, "2024", "notes.txt"), "Not an entry\n")
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	files, err := watchDir(watcher, baseDir)
+	if err != nil {
+		t.Fatalf("watchDir() error = %v", err)
+	}
+
+	wantFiles := []string{
+		filepath.Join(baseDir, "2024", "01", "2024-01-14.md"),
+		filepath.Join(baseDir, "2024", "01", "2024-01-15.md.age"),
+		filepath.Join(baseDir, "2024-01-13.md"),
+	}
+	slices.Sort(files)
+	slices.Sort(wantFiles)
+	if !slices.Equal(files, wantFiles) {
+		t.Errorf("watchDir() files = %v, want %v", files, wantFiles)
+	}
+
+	wantDirs := []string{
+		baseDir,
+		filepath.Join(baseDir, "2024"),
+		filepath.Join(baseDir, "2024", "01"),
+		filepath.Join(baseDir, "2024", "01", "attachments"),
+	}
+	watched := watcher.WatchList()
+	slices.Sort(watched)
+	slices.Sort(wantDirs)
+	if !slices.Equal(watched, wantDirs) {
+		t.Errorf("watched directories = %v, want %v", watched, wantDirs)
+	}
+}
+
+func TestIsUnderAny(t *testing.T) {
+	dir := filepath.Join("journal", "2024")
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"same path", dir, true},
+		{"file in directory", filepath.Join(dir, "2024-01-15.md"), true},
+		{"file in subdirectory", filepath.Join(dir, "01", "2024-01-15.md"), true},
+		{"sibling with common prefix", filepath.Join("journal", "2024-old", "2024-01-15.md"), false},
+		{"other directory", filepath.Join("journal", "2023", "2023-12-31.md"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUnderAny(tt.path, []string{dir}); got != tt.want {
+				t.Errorf("isUnderAny(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_HasEntriesUnder(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	baseDir := t.TempDir()
+	mustWrite(t, filepath.Join(baseDir, "2024", "01", "2024-01-15.md"), "Entry\n")
+	mustWrite(t, filepath.Join(baseDir, "2024", "notes.txt"), "Not an entry\n")
+
+	cfg := &config.Config{Common: config.CommonConfig{BaseDirectory: baseDir}}
+	cfg.SetDefaults()
+	s, err := New(cfg, jnal.NewJournal(cfg), baseDir, false)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := s.reloadEntries(); err != nil {
+		t.Fatalf("reloadEntries() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"entry", filepath.Join(baseDir, "2024", "01", "2024-01-15.md"), true},
+		{"directory with entries", filepath.Join(baseDir, "2024"), true},
+		{"non-entry file", filepath.Join(baseDir, "2024", "notes.txt"), false},
+		{"swap file", filepath.Join(baseDir, "2024", "01", ".2024-01-15.md.swp"), false},
+		{"directory without entries", filepath.Join(baseDir, "attachments"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.hasEntriesUnder(tt.path); got != tt.want {
+				t.Errorf("hasEntriesUnder(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_OnThisDayLinksResolve(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	baseDir := t.TempDir()
+	mustWrite(t, filepath.Join(baseDir, "2024", "2024-01-15-notes.md"), "Notes\n")
+	mustWrite(t, filepath.Join(baseDir, "2023", "2023-01-15.md"), "Entry\n")
+
+	cfg := &config.Config{Common: config.CommonConfig{BaseDirectory: baseDir}}
+	cfg.SetDefaults()
+	s, err := New(cfg, jnal.NewJournal(cfg), baseDir, false)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := s.reloadEntries(); err != nil {
+		t.Fatalf("reloadEntries() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleIndex(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	ids := make(map[string]bool)
+	for _, m := range regexp.MustCompile(`\sid="([^"]*)"`).FindAllStringSubmatch(rec.Body.String(), -1) {
+		ids[m[1]] = true
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleOnThisDay(rec, httptest.NewRequest(http.MethodGet, "/onthisday?date=2025-01-15", nil))
+	links := regexp.MustCompile(`href="/#([^"]*)"`).FindAllStringSubmatch(rec.Body.String(), -1)
+	if len(links) != 2 {
+		t.Fatalf("onthisday links = %v, want 2", links)
+	}
+	for _, m := range links {
+		if !ids[m[1]] {
+			t.Errorf("onthisday links to missing anchor %q", m[1])
+		}
+	}
+}
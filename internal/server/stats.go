@@ -0,0 +1,77 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/longkey1/jnal/internal/jnal"
+	"github.com/longkey1/jnal/internal/util"
+)
+
+// StatsData represents data for the stats template
+type StatsData struct {
+	Title          string
+	CSS            template.CSS
+	CSSDark        template.CSS
+	DarkModeToggle bool
+	Pages          []PageLink
+	Stats          *jnal.Stats
+	Bars           []StatsBar
+	LiveReload     bool
+}
+
+// StatsBar represents a bar in the monthly chart
+type StatsBar struct {
+	Label   string
+	Entries int
+	Words   int
+	Percent int
+}
+
+// handleStats handles the stats page
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.journal.Stats(util.Today())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := StatsData{
+		Title:          s.cfg.Build.Title,
+		CSS:            template.CSS(s.css),
+		CSSDark:        template.CSS(s.cssDark),
+		DarkModeToggle: s.darkModeToggle,
+		Pages:          s.pages(),
+		Stats:          stats,
+		Bars:           monthBars(stats.Months),
+		LiveReload:     s.liveReload,
+	}
+
+	if err := s.tmpl.ExecuteTemplate(w, "stats.html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// monthBars converts monthly statistics to bars, newest first
+func monthBars(months []jnal.PeriodStat) []StatsBar {
+	maxWords := 0
+	for _, m := range months {
+		maxWords = max(maxWords, m.Words)
+	}
+
+	bars := make([]StatsBar, 0, len(months))
+	for i := len(months) - 1; i >= 0; i-- {
+		m := months[i]
+		percent := 0
+		if maxWords > 0 {
+			percent = m.Words * 100 / maxWords
+		}
+		bars = append(bars, StatsBar{
+			Label:   m.Period,
+			Entries: m.Entries,
+			Words:   m.Words,
+			Percent: percent,
+		})
+	}
+	return bars
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/longkey1/jnal/internal/config"
+	"github.com/longkey1/jnal/internal/jnal"
+)
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+// templateFuncs are the functions available in templates
+var templateFuncs = template.FuncMap{
+	"deref": func(f *float64) float64 {
+		if f == nil {
+			return 0
+		}
+		return *f
+	},
+	// formatDate formats a date with a Go layout: {{ .Date | formatDate "Monday, January 2" }}
+	"formatDate": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	// wordCount counts the words of text or rendered HTML: {{ wordCount .Content }}
+	"wordCount": func(content any) int {
+		return jnal.CountWords(plainText(content))
+	},
+	// excerpt returns the beginning of text or rendered HTML as plain text,
+	// limited to a number of characters: {{ excerpt 140 .Content }}
+	"excerpt": func(length int, content any) string {
+		return jnal.Excerpt(plainText(content), length)
+	},
+}
+
+// headingPattern matches rendered headings, which are left out of plain text
+var headingPattern = regexp.MustCompile(`(?s)<h[1-6][^>]*>.*?</h[1-6]>`)
+
+// tagPattern matches HTML tags
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// plainText returns the text of rendered HTML without headings and tags.
+// Other values are formatted as text.
+func plainText(content any) string {
+	switch c := content.(type) {
+	case template.HTML:
+		text := headingPattern.ReplaceAllString(string(c), "")
+		return html.UnescapeString(tagPattern.ReplaceAllString(text, ""))
+	case string:
+		return c
+	default:
+		return fmt.Sprint(c)
+	}
+}
+
+// parseTemplates parses the embedded templates, then the templates of the theme and
+// of template_dir, so that their files override the built-in ones file by file
+func parseTemplates(cfg *config.Config) (*template.Template, error) {
+	tmpl, err := template.New("").Funcs(templateFuncs).ParseFS(templatesFS, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	themeDir, err := cfg.ThemeDir()
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range []string{themeDir, cfg.Build.TemplateDir} {
+		if dir == "" {
+			continue
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("template directory %s not found", dir)
+		}
+		files, err := filepath.Glob(filepath.Join(dir, "*.html"))
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			continue
+		}
+		if tmpl, err = tmpl.ParseFiles(files...); err != nil {
+			return nil, err
+		}
+	}
+
+	return tmpl, nil
+}
+
+// EjectTemplates copies the built-in templates to dir as a starting point for template_dir
+// and returns the paths written. Nothing is written if a template exists in dir, unless force is set.
+func EjectTemplates(dir string, force bool) ([]string, error) {
+	names, err := fs.Glob(templatesFS, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	if !force {
+		for _, name := range names {
+			path := filepath.Join(dir, filepath.Base(name))
+			if _, err := os.Stat(path); err == nil {
+				return nil, fmt.Errorf("%s already exists (use --force to overwrite)", path)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(dir, config.DirPermission); err != nil {
+		return nil, fmt.Errorf("creating template directory: %w", err)
+	}
+
+	var written []string
+	for _, name := range names {
+		data, err := templatesFS.ReadFile(name)
+		if err != nil {
+			return written, err
+		}
+		path := filepath.Join(dir, filepath.Base(name))
+		if err := os.WriteFile(path, data, config.FilePermission); err != nil {
+			return written, fmt.Errorf("writing %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTemplates_Overrides(t *testing.T) {
+	configDir := t.TempDir()
+	templateDir := filepath.Join(configDir, "templates")
+	themeDir := filepath.Join(configDir, "themes", "portal")
+	mustWrite(t, filepath.Join(themeDir, "index.html"), "theme index")
+	mustWrite(t, filepath.Join(themeDir, "print.html"), "theme print")
+	mustWrite(t, filepath.Join(templateDir, "index.html"),
+		`{{ range .Entries }}{{ .Date | formatDate "Jan 2" }}: {{ excerpt 9 .Content }} ({{ wordCount .Content }}){{ end }}`)
+
+	b, _ := newTestBuilder(t, map[string]string{
+		"2024/2024-01-15.md": "# Morning\n\nWent for a long walk by the river.\n",
+	})
+	b.cfg.Dir = configDir
+	b.cfg.Build.Theme = "portal"
+	b.cfg.Build.TemplateDir = templateDir
+
+	tmpl, err := parseTemplates(b.cfg)
+	if err != nil {
+		t.Fatalf("parseTemplates() error = %v", err)
+	}
+	b.tmpl = tmpl
+
+	outputDir := t.TempDir()
+	if err := b.Build(outputDir); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	index, err := os.ReadFile(filepath.Join(outputDir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(index), "Jan 15: Went for… (8)"; got != want {
+		t.Errorf("index.html = %q, want %q", got, want)
+	}
+
+	// Files missing from template_dir come from the theme, then the built-in templates
+	for name, want := range map[string]string{"print.html": "theme print", "stats.html": "<title>Stats"} {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, name, StatsData{}); err != nil {
+			t.Fatalf("executing %s: %v", name, err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s = %q, want %q", name, buf.String(), want)
+		}
+	}
+}
+
+func TestParseTemplates_MissingDir(t *testing.T) {
+	b, _ := newTestBuilder(t, nil)
+	b.cfg.Build.TemplateDir = filepath.Join(t.TempDir(), "missing")
+	if _, err := parseTemplates(b.cfg); err == nil {
+		t.Error("parseTemplates() error = nil, want missing directory error")
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	content := template.HTML("<h4>Title</h4>\n<p>Fish &amp; chips <em>today</em></p>\n")
+
+	if got := templateFuncs["wordCount"].(func(any) int)(content); got != 4 {
+		t.Errorf("wordCount = %d, want 4", got)
+	}
+	if got := templateFuncs["excerpt"].(func(int, any) string)(10, content); got != "Fish & chi…" {
+		t.Errorf("excerpt = %q", got)
+	}
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	if got := templateFuncs["formatDate"].(func(string, time.Time) string)("Monday, January 2", date); got != "Monday, January 15" {
+		t.Errorf("formatDate = %q", got)
+	}
+}
+
+func TestEjectTemplates(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "templates")
+	paths, err := EjectTemplates(dir, false)
+	if err != nil {
+		t.Fatalf("EjectTemplates() error = %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no templates ejected")
+	}
+	embedded, err := templatesFS.ReadFile("templates/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ejected, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil || !bytes.Equal(ejected, embedded) {
+		t.Errorf("ejected index.html differs from the built-in one: %v", err)
+	}
+
+	mustWrite(t, filepath.Join(dir, "index.html"), "custom")
+	if _, err := EjectTemplates(dir, false); err == nil {
+		t.Error("EjectTemplates() over existing templates succeeded without force")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "index.html")); string(data) != "custom" {
+		t.Error("existing template overwritten without force")
+	}
+	if _, err := EjectTemplates(dir, true); err != nil {
+		t.Errorf("EjectTemplates() with force error = %v", err)
+	}
+}
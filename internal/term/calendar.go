@@ -0,0 +1,173 @@
+package term
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ANSI escape sequences
+const (
+	ansiReset   = "\033[0m"
+	ansiEntry   = "\033[1;32m"
+	ansiReverse = "\033[7m"
+)
+
+// cellWidth is the width of a day in the calendar grid (two digits and a marker)
+const cellWidth = 3
+
+// CalendarOptions represents options for drawing calendars
+type CalendarOptions struct {
+	// WeekStart is the first day of the week (time.Sunday or time.Monday)
+	WeekStart time.Weekday
+	// WeekNumbers shows ISO week numbers in front of each week
+	WeekNumbers bool
+	// Color highlights days with ANSI colors instead of markers
+	Color bool
+	// Marked reports whether the day has an entry
+	Marked func(time.Time) bool
+	// Today is highlighted when Color is enabled
+	Today time.Time
+}
+
+// Month draws a cal(1)-style month grid and returns its lines, all of the same width
+func Month(year int, month time.Month, opts CalendarOptions) []string {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+	width := monthWidth(opts)
+
+	var lines []string
+
+	// Title and weekday header
+	lines = append(lines, center(first.Format("January 2006"), width))
+	var header strings.Builder
+	if opts.WeekNumbers {
+		header.WriteString("Wk ")
+	}
+	for i := 0; i < 7; i++ {
+		wd := time.Weekday((int(opts.WeekStart) + i) % 7)
+		header.WriteString(wd.String()[:2] + " ")
+	}
+	lines = append(lines, header.String())
+
+	// Always draw six weeks so that months line up side by side
+	offset := (int(first.Weekday()) - int(opts.WeekStart) + 7) % 7
+	start := first.AddDate(0, 0, -offset)
+	for w := 0; w < 6; w++ {
+		var line strings.Builder
+		weekStart := start.AddDate(0, 0, w*7)
+
+		if opts.WeekNumbers {
+			if inMonthWeek(weekStart, first, last) {
+				_, week := isoWeekOf(weekStart, opts.WeekStart)
+				fmt.Fprintf(&line, "%2d ", week)
+			} else {
+				line.WriteString("   ")
+			}
+		}
+
+		for i := 0; i < 7; i++ {
+			d := weekStart.AddDate(0, 0, i)
+			if d.Before(first) || d.After(last) {
+				line.WriteString(strings.Repeat(" ", cellWidth))
+				continue
+			}
+			line.WriteString(dayCell(d, opts))
+		}
+		lines = append(lines, line.String())
+	}
+
+	return lines
+}
+
+// Months draws months side by side, columns months per row
+func Months(months []time.Time, columns int, opts CalendarOptions) string {
+	var sb strings.Builder
+	gap := "  "
+
+	for i := 0; i < len(months); i += columns {
+		row := months[i:min(i+columns, len(months))]
+		grids := make([][]string, len(row))
+		for j, m := range row {
+			grids[j] = Month(m.Year(), m.Month(), opts)
+		}
+
+		var lines []string
+		for line := range grids[0] {
+			parts := make([]string, len(grids))
+			for j := range grids {
+				parts[j] = grids[j][line]
+			}
+			lines = append(lines, strings.TrimRight(strings.Join(parts, gap), " "))
+		}
+		// Drop the padding weeks that no month in the row needs
+		for len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		for _, line := range lines {
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+		if i+columns < len(months) {
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// dayCell draws a single day
+func dayCell(d time.Time, opts CalendarOptions) string {
+	marked := opts.Marked != nil && opts.Marked(d)
+	day := fmt.Sprintf("%2d", d.Day())
+
+	if !opts.Color {
+		if marked {
+			return day + "*"
+		}
+		return day + " "
+	}
+
+	isToday := d.Year() == opts.Today.Year() && d.YearDay() == opts.Today.YearDay()
+	switch {
+	case marked && isToday:
+		return ansiEntry + ansiReverse + day + ansiReset + " "
+	case marked:
+		return ansiEntry + day + ansiReset + " "
+	case isToday:
+		return ansiReverse + day + ansiReset + " "
+	default:
+		return day + " "
+	}
+}
+
+// monthWidth returns the width of a month grid
+func monthWidth(opts CalendarOptions) int {
+	width := 7 * cellWidth
+	if opts.WeekNumbers {
+		width += cellWidth
+	}
+	return width
+}
+
+// inMonthWeek reports whether the week starting at weekStart has a day in the month
+func inMonthWeek(weekStart, first, last time.Time) bool {
+	weekEnd := weekStart.AddDate(0, 0, 6)
+	return !weekEnd.Before(first) && !weekStart.After(last)
+}
+
+// isoWeekOf returns the ISO week of the week starting at weekStart.
+// Weeks starting on Sunday are numbered after their Monday.
+func isoWeekOf(weekStart time.Time, start time.Weekday) (int, int) {
+	monday := weekStart.AddDate(0, 0, (int(time.Monday)-int(start)+7)%7)
+	return monday.ISOWeek()
+}
+
+// center centers s within width
+func center(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	left := (width - len(s)) / 2
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", width-len(s)-left)
+}
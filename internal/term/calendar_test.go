@@ -0,0 +1,66 @@
+package term
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonth(t *testing.T) {
+	marked := func(d time.Time) bool {
+		return d.Day() == 15
+	}
+
+	tests := []struct {
+		name string
+		opts CalendarOptions
+		want []string
+	}{
+		{
+			name: "sunday start",
+			opts: CalendarOptions{WeekStart: time.Sunday, Marked: marked},
+			want: []string{
+				"    January 2024     ",
+				"Su Mo Tu We Th Fr Sa ",
+				"    1  2  3  4  5  6 ",
+				" 7  8  9 10 11 12 13 ",
+				"14 15*16 17 18 19 20 ",
+				"21 22 23 24 25 26 27 ",
+				"28 29 30 31          ",
+			},
+		},
+		{
+			name: "monday start with week numbers",
+			opts: CalendarOptions{WeekStart: time.Monday, WeekNumbers: true, Marked: marked},
+			want: []string{
+				"      January 2024      ",
+				"Wk Mo Tu We Th Fr Sa Su ",
+				" 1  1  2  3  4  5  6  7 ",
+				" 2  8  9 10 11 12 13 14 ",
+				" 3 15*16 17 18 19 20 21 ",
+				" 4 22 23 24 25 26 27 28 ",
+				" 5 29 30 31             ",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Month(2024, time.January, tt.opts)
+			for i, want := range tt.want {
+				if strings.TrimRight(got[i], " ") != strings.TrimRight(want, " ") {
+					t.Errorf("line %d = %q, want %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]int{0, 4, 8}); got != "▁▄█" {
+		t.Errorf("Sparkline() = %q, want %q", got, "▁▄█")
+	}
+	if got := Sparkline([]int{0, 0}); got != "▁▁" {
+		t.Errorf("Sparkline() = %q, want %q", got, "▁▁")
+	}
+}
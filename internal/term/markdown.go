@@ -0,0 +1,354 @@
+package term
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// ANSI escape sequences for Markdown styles
+const (
+	ansiBold      = "\033[1m"
+	ansiItalic    = "\033[3m"
+	ansiUnderline = "\033[4m"
+	ansiStrike    = "\033[9m"
+	ansiDim       = "\033[2m"
+	ansiHeading   = "\033[1;36m"
+	ansiCode      = "\033[33m"
+	ansiLink      = "\033[4;34m"
+)
+
+// markdownParser parses Markdown for terminal rendering
+var markdownParser = goldmark.New(
+	goldmark.WithExtensions(extension.Strikethrough, extension.Table, extension.TaskList),
+).Parser()
+
+// RenderMarkdown renders Markdown for the terminal, wrapping text to width.
+// Styles are only emitted when color is true.
+func RenderMarkdown(source []byte, width int, color bool) string {
+	doc := markdownParser.Parse(text.NewReader(source))
+	r := &mdRenderer{source: source, width: max(width, 20), color: color}
+
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		r.block(n, "", "")
+	}
+
+	return strings.TrimRight(r.out.String(), "\n") + "\n"
+}
+
+// mdRenderer renders a Markdown AST as terminal text
+type mdRenderer struct {
+	source []byte
+	width  int
+	color  bool
+	out    bytes.Buffer
+}
+
+// block renders a block node. first is the prefix of the first line, rest of the following lines.
+func (r *mdRenderer) block(n ast.Node, first, rest string) {
+	switch node := n.(type) {
+	case *ast.Heading:
+		style := ansiHeading
+		if node.Level == 1 {
+			style += ansiUnderline
+		}
+		r.paragraph(r.style(style, strings.Repeat("#", node.Level)+" "+r.inlines(n, style)), first, rest)
+		r.out.WriteString("\n")
+
+	case *ast.Paragraph:
+		r.paragraph(r.inlines(n, ""), first, rest)
+		r.blankAfter(n)
+
+	case *ast.TextBlock:
+		r.paragraph(r.inlines(n, ""), first, rest)
+
+	case *ast.List:
+		number := node.Start
+		for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+			marker := "• "
+			if node.IsOrdered() {
+				marker = fmt.Sprintf("%d. ", number)
+				number++
+			}
+			indent := rest + strings.Repeat(" ", len([]rune(marker)))
+			if item == n.FirstChild() {
+				r.children(item, first+marker, indent)
+			} else {
+				r.children(item, rest+marker, indent)
+			}
+		}
+		if n.Parent().Kind() == ast.KindDocument {
+			r.out.WriteString("\n")
+		}
+
+	case *ast.Blockquote:
+		bar := r.style(ansiDim, "│") + " "
+		r.children(n, first+bar, rest+bar)
+
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		lines := n.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			line := lines.At(i)
+			prefix := rest
+			if i == 0 {
+				prefix = first
+			}
+			code := strings.TrimRight(string(line.Value(r.source)), "\n")
+			r.out.WriteString(prefix + "    " + r.style(ansiCode, code) + "\n")
+		}
+		r.out.WriteString("\n")
+
+	case *ast.ThematicBreak:
+		r.out.WriteString(first + r.style(ansiDim, strings.Repeat("─", r.width-len([]rune(rest)))) + "\n\n")
+
+	case *ast.HTMLBlock:
+		lines := n.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			line := lines.At(i)
+			r.out.WriteString(rest + r.style(ansiDim, strings.TrimRight(string(line.Value(r.source)), "\n")) + "\n")
+		}
+
+	case *east.Table:
+		for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+			var cells []string
+			for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+				style := ""
+				if row.Kind() == east.KindTableHeader {
+					style = ansiBold
+				}
+				cells = append(cells, r.inlines(cell, style))
+			}
+			r.out.WriteString(rest + strings.Join(cells, r.style(ansiDim, " │ ")) + "\n")
+		}
+		r.out.WriteString("\n")
+
+	default:
+		r.children(n, first, rest)
+	}
+}
+
+// children renders the child blocks of n, using first as the prefix of the very first line
+func (r *mdRenderer) children(n ast.Node, first, rest string) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if c == n.FirstChild() {
+			r.block(c, first, rest)
+		} else {
+			r.block(c, rest, rest)
+		}
+	}
+}
+
+// blankAfter writes a blank line after a paragraph unless it is in a tight list
+func (r *mdRenderer) blankAfter(n ast.Node) {
+	if item, ok := n.Parent().(*ast.ListItem); ok {
+		if list, ok := item.Parent().(*ast.List); ok && list.IsTight {
+			return
+		}
+		if n.NextSibling() == nil {
+			return
+		}
+	}
+	r.out.WriteString("\n")
+}
+
+// paragraph writes wrapped text with the given line prefixes
+func (r *mdRenderer) paragraph(s, first, rest string) {
+	for _, hard := range strings.Split(s, "\n") {
+		for _, line := range wrap(hard, r.width-visibleWidth(rest)) {
+			r.out.WriteString(first + line + "\n")
+			first = rest
+		}
+	}
+}
+
+// inlines renders the inline children of n. outer is the style active around them.
+func (r *mdRenderer) inlines(n ast.Node, outer string) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		sb.WriteString(r.inline(c, outer))
+	}
+	return sb.String()
+}
+
+// inline renders an inline node
+func (r *mdRenderer) inline(n ast.Node, outer string) string {
+	switch node := n.(type) {
+	case *ast.Text:
+		s := string(node.Value(r.source))
+		switch {
+		case node.HardLineBreak():
+			s += "\n"
+		case node.SoftLineBreak():
+			s += " "
+		}
+		return s
+
+	case *ast.String:
+		return string(node.Value)
+
+	case *ast.CodeSpan:
+		var sb strings.Builder
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			if t, ok := c.(*ast.Text); ok {
+				sb.Write(t.Value(r.source))
+			}
+		}
+		return r.nested(ansiCode, sb.String(), outer)
+
+	case *ast.Emphasis:
+		style := ansiItalic
+		if node.Level >= 2 {
+			style = ansiBold
+		}
+		return r.nested(style, r.inlines(n, outer+style), outer)
+
+	case *east.Strikethrough:
+		return r.nested(ansiStrike, r.inlines(n, outer+ansiStrike), outer)
+
+	case *ast.Link:
+		label := r.nested(ansiLink, r.inlines(n, outer+ansiLink), outer)
+		dest := string(node.Destination)
+		if dest == "" || strings.HasPrefix(dest, "#") {
+			return label
+		}
+		return label + " " + r.nested(ansiDim, "<"+dest+">", outer)
+
+	case *ast.AutoLink:
+		return r.nested(ansiLink, string(node.URL(r.source)), outer)
+
+	case *ast.Image:
+		alt := r.inlines(n, outer)
+		if alt == "" {
+			alt = "image"
+		}
+		return r.nested(ansiDim, "["+alt+": "+string(node.Destination)+"]", outer)
+
+	case *ast.RawHTML:
+		return ""
+
+	case *east.TaskCheckBox:
+		if node.IsChecked {
+			return "[x] "
+		}
+		return "[ ] "
+
+	default:
+		return r.inlines(n, outer)
+	}
+}
+
+// nested applies style to s and restores the outer style afterwards
+func (r *mdRenderer) nested(style, s, outer string) string {
+	if !r.color {
+		return s
+	}
+	return style + s + ansiReset + outer
+}
+
+// style applies style to s
+func (r *mdRenderer) style(style, s string) string {
+	return r.nested(style, s, "")
+}
+
+// wrap wraps text containing ANSI sequences to the given visible width.
+// Lines break at spaces and between CJK characters.
+func wrap(s string, width int) []string {
+	var lines []string
+	var line, word strings.Builder
+	lineWidth, wordWidth := 0, 0
+	pendingSpace := false
+
+	flushWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		need := wordWidth
+		if pendingSpace {
+			need++
+		}
+		if lineWidth > 0 && lineWidth+need > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+			pendingSpace = false
+		}
+		if pendingSpace && lineWidth > 0 {
+			line.WriteByte(' ')
+			lineWidth++
+		}
+		line.WriteString(word.String())
+		lineWidth += wordWidth
+		word.Reset()
+		wordWidth = 0
+		pendingSpace = false
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == '\033':
+			// Copy the escape sequence without counting its width
+			j := i
+			for j < len(runes) && runes[j] != 'm' {
+				j++
+			}
+			word.WriteString(string(runes[i:min(j+1, len(runes))]))
+			i = j
+		case unicode.IsSpace(c):
+			flushWord()
+			pendingSpace = true
+		case isWide(c):
+			flushWord()
+			word.WriteRune(c)
+			wordWidth = 2
+			flushWord()
+		default:
+			word.WriteRune(c)
+			wordWidth++
+		}
+	}
+	flushWord()
+
+	if line.Len() > 0 || len(lines) == 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// visibleWidth returns the display width of s, ignoring ANSI sequences
+func visibleWidth(s string) int {
+	width := 0
+	inEscape := false
+	for _, c := range s {
+		switch {
+		case c == '\033':
+			inEscape = true
+		case inEscape:
+			if c == 'm' {
+				inEscape = false
+			}
+		case isWide(c):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// isWide reports whether c is displayed with double width
+func isWide(c rune) bool {
+	return unicode.Is(unicode.Han, c) ||
+		unicode.Is(unicode.Hiragana, c) ||
+		unicode.Is(unicode.Katakana, c) ||
+		unicode.Is(unicode.Hangul, c) ||
+		(c >= 0xff01 && c <= 0xff60) // Fullwidth forms
+}
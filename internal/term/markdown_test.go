@@ -0,0 +1,44 @@
+package term
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{name: "fits", text: "short line", width: 20, want: []string{"short line"}},
+		{name: "breaks at spaces", text: "one two three four", width: 9, want: []string{"one two", "three", "four"}},
+		{name: "long word", text: "abcdefghij k", width: 5, want: []string{"abcdefghij", "k"}},
+		{name: "cjk", text: "今日は晴れ", width: 6, want: []string{"今日は", "晴れ"}},
+		{name: "ansi", text: "\033[1mbold\033[0m text", width: 4, want: []string{"\033[1mbold\033[0m", "text"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrap(tt.text, tt.width)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("wrap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	source := "# Title\n\nSome **bold** text.\n\n- one\n- two\n"
+	want := "# Title\n\nSome bold text.\n\n• one\n• two\n"
+
+	if got := RenderMarkdown([]byte(source), 80, false); got != want {
+		t.Errorf("RenderMarkdown() = %q, want %q", got, want)
+	}
+
+	colored := RenderMarkdown([]byte(source), 80, true)
+	if !strings.Contains(colored, ansiBold+"bold"+ansiReset) {
+		t.Errorf("RenderMarkdown() with color = %q, want bold text", colored)
+	}
+}
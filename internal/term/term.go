@@ -0,0 +1,195 @@
+package term
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// defaultWidth is the width used when the terminal width is unknown
+const defaultWidth = 80
+
+// sparkTicks are the characters used to draw sparklines, from lowest to highest
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// heatLevels are the characters used to draw heatmap cells, from no activity to highest
+var heatLevels = []string{"·", "░", "▒", "▓", "█"}
+
+// IsTerminal reports whether the file is a terminal
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// ReadPassword prompts on stderr and reads a line from the terminal on stdin without echoing it
+func ReadPassword(prompt string) (string, error) {
+	if !IsTerminal(os.Stdin) {
+		return "", fmt.Errorf("stdin is not a terminal")
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	pass, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	return string(pass), nil
+}
+
+// Width returns the width of the terminal, falling back to $COLUMNS or 80
+func Width(f *os.File) int {
+	if w, _, err := term.GetSize(int(f.Fd())); err == nil && w > 0 {
+		return w
+	}
+	var w int
+	if _, err := fmt.Sscanf(os.Getenv("COLUMNS"), "%d", &w); err == nil && w > 0 {
+		return w
+	}
+	return defaultWidth
+}
+
+// Page writes content through $PAGER (default: less) when out is a terminal,
+// and directly to out otherwise
+func Page(out *os.File, content string) error {
+	if !IsTerminal(out) {
+		_, err := io.WriteString(out, content)
+		return err
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	return runPager(pager, out, content)
+}
+
+// commandNotFound is the exit status of sh when the command doesn't exist
+const commandNotFound = 127
+
+// runPager pipes content through the pager command, falling back to
+// writing it directly to out if the pager is not available
+func runPager(pager string, out io.Writer, content string) error {
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	// Let less show colors and exit if the content fits on one screen
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() != commandNotFound:
+		return fmt.Errorf("running pager %q: %w", pager, err)
+	}
+
+	// Fall back to plain output if the pager (or sh) is not available
+	_, err = io.WriteString(out, content)
+	return err
+}
+
+// Sparkline draws the values as a single line of bar characters
+func Sparkline(values []int) string {
+	maxValue := 0
+	for _, v := range values {
+		maxValue = max(maxValue, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		if maxValue == 0 {
+			sb.WriteRune(sparkTicks[0])
+			continue
+		}
+		sb.WriteRune(sparkTicks[v*(len(sparkTicks)-1)/maxValue])
+	}
+	return sb.String()
+}
+
+// Heatmap draws a GitHub-style heatmap of daily values for the given number of weeks ending at end.
+// Rows are weekdays starting with Monday, columns are weeks. values is keyed by yyyy-mm-dd.
+func Heatmap(values map[string]int, end time.Time, weeks int) string {
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	// Start on the Monday of the first week
+	start := end.AddDate(0, 0, -(weeks-1)*7-(int(end.Weekday())+6)%7)
+
+	maxValue := 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		maxValue = max(maxValue, values[d.Format("2006-01-02")])
+	}
+
+	var sb strings.Builder
+
+	// Month labels above the first week of each month
+	sb.WriteString("    ")
+	label := ""
+	for w := 0; w < weeks; w++ {
+		monday := start.AddDate(0, 0, w*7)
+		if label != "" {
+			label = label[1:]
+			continue
+		}
+		// Label a partial first week only if its month has room for the label
+		if monday.Day() <= 7 || (w == 0 && monday.Day() <= 14) {
+			label = monday.Format("Jan")
+			sb.WriteString(label)
+			label = label[1:]
+			continue
+		}
+		sb.WriteString(" ")
+	}
+	sb.WriteString("\n")
+
+	for row := 0; row < 7; row++ {
+		day := start.AddDate(0, 0, row)
+		if row%2 == 0 {
+			sb.WriteString(day.Format("Mon")[:3] + " ")
+		} else {
+			sb.WriteString("    ")
+		}
+		for w := 0; w < weeks; w++ {
+			d := start.AddDate(0, 0, w*7+row)
+			if d.After(end) {
+				break
+			}
+			v, ok := values[d.Format("2006-01-02")]
+			sb.WriteString(heatLevel(v, ok, maxValue))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// heatLevel returns the heatmap character for a value
+func heatLevel(v int, ok bool, maxValue int) string {
+	if !ok {
+		return heatLevels[0]
+	}
+	if maxValue == 0 {
+		return heatLevels[1]
+	}
+	levels := len(heatLevels) - 1
+	level := 1 + v*(levels-1)/maxValue
+	return heatLevels[min(level, levels)]
+}
+
+// Separator draws a horizontal line of the given width with a label
+func Separator(label string, width int, color bool) string {
+	line := "── " + label + " "
+	if rest := width - len([]rune(line)); rest > 0 {
+		line += strings.Repeat("─", rest)
+	}
+	if color {
+		return ansiBold + line + ansiReset
+	}
+	return line
+}
@@ -0,0 +1,32 @@
+package term
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunPager(t *testing.T) {
+	tests := []struct {
+		name    string
+		pager   string
+		want    string
+		wantErr bool
+	}{
+		{name: "pager", pager: "cat", want: "entry\n"},
+		{name: "missing pager falls back", pager: "jnal-no-such-pager", want: "entry\n"},
+		{name: "failing pager", pager: "exit 3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			err := runPager(tt.pager, &out, "entry\n")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runPager() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -40,3 +40,44 @@ func Today() time.Time {
 func Format(t time.Time) string {
 	return t.Format(ISO8601Date)
 }
+
+// DateRange represents an inclusive range of dates. A zero bound means unbounded.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// ParseRange parses optional from/to dates in ISO8601 format (yyyy-mm-dd).
+// An empty string leaves the corresponding bound open.
+func ParseRange(from, to string) (DateRange, error) {
+	var r DateRange
+	var err error
+
+	if from != "" {
+		if r.From, err = Parse(from); err != nil {
+			return DateRange{}, err
+		}
+	}
+	if to != "" {
+		if r.To, err = Parse(to); err != nil {
+			return DateRange{}, err
+		}
+	}
+	if !r.From.IsZero() && !r.To.IsZero() && r.From.After(r.To) {
+		return DateRange{}, fmt.Errorf("invalid date range: %s is after %s", from, to)
+	}
+
+	return r, nil
+}
+
+// Contains reports whether the date is within the range
+func (r DateRange) Contains(t time.Time) bool {
+	day := Format(t)
+	if !r.From.IsZero() && day < Format(r.From) {
+		return false
+	}
+	if !r.To.IsZero() && day > Format(r.To) {
+		return false
+	}
+	return true
+}
@@ -108,3 +108,56 @@ func TestFormat(t *testing.T) {
 		t.Errorf("Format() = %v, want %v", got, want)
 	}
 }
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{name: "both bounds", from: "2024-01-01", to: "2024-01-31", wantErr: false},
+		{name: "same day", from: "2024-01-15", to: "2024-01-15", wantErr: false},
+		{name: "open end", from: "2024-01-01", wantErr: false},
+		{name: "open start", to: "2024-01-31", wantErr: false},
+		{name: "unbounded", wantErr: false},
+		{name: "reversed", from: "2024-02-01", to: "2024-01-01", wantErr: true},
+		{name: "invalid from", from: "2024/01/01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRange(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDateRange_Contains(t *testing.T) {
+	r, err := ParseRange("2024-01-10", "2024-01-20")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		date time.Time
+		want bool
+	}{
+		{date: time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC), want: false},
+		{date: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), want: true},
+		{date: time.Date(2024, 1, 20, 23, 0, 0, 0, time.Local), want: true},
+		{date: time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC), want: false},
+	}
+
+	for _, tt := range tests {
+		if got := r.Contains(tt.date); got != tt.want {
+			t.Errorf("Contains(%s) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+
+	if !(DateRange{}).Contains(time.Now()) {
+		t.Error("unbounded range should contain any date")
+	}
+}